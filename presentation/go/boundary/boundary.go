@@ -0,0 +1,198 @@
+// Package boundary runs a chain of named steps and keeps error handling out
+// of them: the first failing step stops the chain, and the failure is handed
+// to a Handler that decides what the caller sees.
+package boundary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// Logger receives every error a Handler returns.
+var Logger = log.New(io.Discard, "boundary: ", log.LstdFlags)
+
+// Step is one named stage of a Pipeline.
+type Step struct {
+	Name       string
+	Run        func(interface{}) (interface{}, error)
+	Conditions []Condition
+
+	// source is where the step's function is defined, when Run wraps it.
+	source string
+}
+
+// Pipeline is an ordered list of steps, each fed the previous step's output.
+type Pipeline struct {
+	steps []Step
+}
+
+// New returns a Pipeline that runs steps in order.
+func New(steps ...Step) *Pipeline {
+	return &Pipeline{steps: steps}
+}
+
+// Steps returns the step names in the order they run.
+func (p *Pipeline) Steps() []string {
+	names := make([]string, len(p.steps))
+	for i, s := range p.steps {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Run feeds input through the steps, stopping at the first failure. Each
+// step that runs is recorded in the Result's Trace.
+func (p *Pipeline) Run(input interface{}) *Result {
+	r := &Result{Value: input}
+	for _, s := range p.steps {
+		e := Entry{Step: s.Name, Source: s.source, Input: r.Value}
+		if e.Source == "" {
+			e.Source = source(s.Run)
+		}
+
+		start := time.Now()
+		out, err := call(s, r.Value)
+		e.Duration = time.Since(start)
+
+		if err != nil {
+			e.Err = err.Error()
+			r.Trace = append(r.Trace, e)
+			r.Step = s.Name
+			r.Err = &StepError{Step: s.Name, Err: err, Trace: r.Trace}
+			return r
+		}
+		e.Output = out
+		r.Trace = append(r.Trace, e)
+		r.Value = out
+	}
+	return r
+}
+
+func call(s Step, input interface{}) (out interface{}, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: panicStack()}
+		}
+	}()
+	if err := check(s.Conditions, false, input); err != nil {
+		return nil, err
+	}
+	out, err = s.Run(input)
+	if err != nil {
+		return out, err
+	}
+	return out, check(s.Conditions, true, out)
+}
+
+// Result is the outcome of a Pipeline run. When a step failed, Value is the
+// input that step was given, Step is its name and Err is a *StepError.
+type Result struct {
+	Value interface{}
+	Step  string
+	Err   error
+	Trace Trace
+}
+
+// Failed reports whether a step failed.
+func (r *Result) Failed() bool {
+	return r.Err != nil
+}
+
+// OnError hands a failure to h and returns the result with whatever error h
+// produced. A successful run returns the final value and a nil error. If h
+// accepts a partial failure by returning nil, the step's partial output is
+// returned in place of its input.
+func (r *Result) OnError(h Handler) (interface{}, error) {
+	if !r.Failed() {
+		return r.Value, nil
+	}
+	err := h.Handle(r.Step, r.Value, r.Err)
+	if err != nil {
+		Logger.Println(err)
+		return r.Value, err
+	}
+	if p, ok := r.partial(); ok {
+		return p.Value, nil
+	}
+	return r.Value, nil
+}
+
+// Partial returns what a partial failure left out of the step's output,
+// the Err of its *PartialError, or nil if the run didn't fail partially.
+// Callers that accept partial output should pass it on, so the failure
+// isn't lost.
+func (r *Result) Partial() error {
+	if p, ok := r.partial(); ok {
+		return p.Err
+	}
+	return nil
+}
+
+func (r *Result) partial() (*PartialError, bool) {
+	var p *PartialError
+	return p, errors.As(r.Err, &p)
+}
+
+// Handler turns a step failure into the error a caller sees.
+type Handler interface {
+	Handle(step string, input interface{}, err error) error
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(step string, input interface{}, err error) error
+
+// Handle calls f.
+func (f HandlerFunc) Handle(step string, input interface{}, err error) error {
+	return f(step, input, err)
+}
+
+// StepError records which step failed and the trace of the run up to and
+// including it.
+type StepError struct {
+	Step  string
+	Err   error
+	Trace Trace
+}
+
+func (e *StepError) Error() string {
+	return e.Step + ": " + e.Err.Error()
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is the failure of a step that produced some output, Value,
+// but not all of it. A Handler decides whether Value is good enough.
+type PartialError struct {
+	Value interface{}
+	Err   error
+}
+
+// Partial returns a *PartialError for a step to fail with when it has a
+// usable partial output.
+func Partial(value interface{}, err error) error {
+	return &PartialError{Value: value, Err: err}
+}
+
+func (e *PartialError) Error() string {
+	return "partial: " + e.Err.Error()
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is returned for a step that panicked. Stack starts at the
+// function that panicked.
+type PanicError struct {
+	Value interface{}
+	Stack []string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
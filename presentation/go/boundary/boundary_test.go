@@ -0,0 +1,186 @@
+package boundary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errDefault = errors.New("default")
+var errCustom = errors.New("custom")
+var errExtra = errors.New("extra")
+
+func add(n int) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		return v.(int) + n, nil
+	}
+}
+
+func times(n int) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		return v.(int) * n, nil
+	}
+}
+
+func blowUp(interface{}) (interface{}, error) {
+	return nil, errors.New("boom")
+}
+
+var testHandler = HandlerFunc(func(step string, input interface{}, err error) error {
+	if step == "custom_blow_up" {
+		return errCustom
+	}
+	return errDefault
+})
+
+func TestReturnsAnError(t *testing.T) {
+	_, err := New(Step{Name: "blow_up", Run: blowUp}).Run(1).OnError(testHandler)
+
+	if err != errDefault {
+		t.Fatalf("got %v, want %v", err, errDefault)
+	}
+}
+
+func TestDispatchesOnStepName(t *testing.T) {
+	_, err := New(Step{Name: "custom_blow_up", Run: blowUp}).Run(1).OnError(testHandler)
+
+	if err != errCustom {
+		t.Fatalf("got %v, want %v", err, errCustom)
+	}
+}
+
+func TestReturnsAResult(t *testing.T) {
+	result, err := New(Step{Name: "add_1", Run: add(1)}).Run(1).OnError(testHandler)
+
+	if err != nil || result != 2 {
+		t.Fatalf("got %v, %v; want 2, nil", result, err)
+	}
+}
+
+func TestChainsSteps(t *testing.T) {
+	result, err := New(
+		Step{Name: "add_1", Run: add(1)},
+		Step{Name: "add_2", Run: add(2)},
+		Step{Name: "times_3", Run: times(3)},
+	).Run(1).OnError(testHandler)
+
+	if err != nil || result != 12 {
+		t.Fatalf("got %v, %v; want 12, nil", result, err)
+	}
+}
+
+func TestStopsAtFirstFailure(t *testing.T) {
+	r := New(
+		Step{Name: "add_1", Run: add(1)},
+		Step{Name: "blow_up", Run: blowUp},
+		Step{Name: "times_3", Run: times(3)},
+	).Run(1)
+
+	if r.Step != "blow_up" || r.Value != 2 {
+		t.Fatalf("got step %q value %v; want blow_up, 2", r.Step, r.Value)
+	}
+}
+
+func TestRecoversPanics(t *testing.T) {
+	r := New(Step{Name: "panics", Run: func(interface{}) (interface{}, error) {
+		panic("woops!")
+	}}).Run(1)
+
+	var p *PanicError
+	if !errors.As(r.Err, &p) || p.Value != "woops!" {
+		t.Fatalf("got %v, want a PanicError", r.Err)
+	}
+}
+
+func TestHandlersDispatchOnStepName(t *testing.T) {
+	p := New(Step{Name: "custom_blow_up", Run: blowUp})
+	h, err := NewHandlers(p.Steps(), func(interface{}, error) error {
+		return errDefault
+	}, map[string]StepHandler{
+		"custom_blow_up": func(input interface{}, err error) error {
+			if input == 0 {
+				return errExtra
+			}
+			return errCustom
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.Run(1).OnError(h); err != errCustom {
+		t.Fatalf("got %v, want %v", err, errCustom)
+	}
+	if _, err := p.Run(0).OnError(h); err != errExtra {
+		t.Fatalf("got %v, want %v", err, errExtra)
+	}
+}
+
+func TestHandlersFallBackToDefault(t *testing.T) {
+	p := New(Step{Name: "not_handled", Run: blowUp})
+	h, _ := NewHandlers(p.Steps(), func(interface{}, error) error {
+		return errDefault
+	}, nil)
+
+	if _, err := p.Run(1).OnError(h); err != errDefault {
+		t.Fatalf("got %v, want %v", err, errDefault)
+	}
+}
+
+func TestHandlersImplementDefaultByThemselves(t *testing.T) {
+	p := New(Step{Name: "add_1", Run: add(1)}, Step{Name: "not_handled", Run: blowUp})
+	h, _ := NewHandlers(p.Steps(), nil, nil)
+
+	_, err := p.Run(1).OnError(h)
+
+	var d *DefaultError
+	if !errors.As(err, &d) || d.Step != "not_handled" || d.UserInfo() != DefaultI18n {
+		t.Fatalf("got %v, want a DefaultError for not_handled", err)
+	}
+	if len(d.Stack) == 0 {
+		t.Fatal("want a call stack")
+	}
+	if !errors.Is(err, &Error{I18n: DefaultI18n}) {
+		t.Fatal("want errors.Is to match the default i18n key")
+	}
+}
+
+func TestDefaultErrorStackStartsAtTheCallerOfHandle(t *testing.T) {
+	h, _ := NewHandlers([]string{"not_handled"}, nil, nil)
+
+	err := h.Handle("not_handled", 1, errCustom).(*DefaultError)
+
+	if !strings.Contains(err.Stack[0], "TestDefaultErrorStackStartsAtTheCallerOfHandle") {
+		t.Fatalf("got first frame %q, want the caller of Handle", err.Stack[0])
+	}
+}
+
+func explode(interface{}) (interface{}, error) {
+	panic("woops!")
+}
+
+func TestDefaultErrorStackStartsWhereTheStepPanicked(t *testing.T) {
+	p := New(Step{Name: "explode", Run: explode})
+	h, _ := NewHandlers(p.Steps(), nil, nil)
+
+	_, err := p.Run(1).OnError(h)
+
+	d := err.(*DefaultError)
+	if !strings.Contains(d.Stack[0], "boundary.explode") {
+		t.Fatalf("got first frame %q, want the step that panicked", d.Stack[0])
+	}
+}
+
+func TestHandlersRejectUnknownSteps(t *testing.T) {
+	p := New(Step{Name: "add_1", Run: add(1)})
+	_, err := NewHandlers(p.Steps(), func(interface{}, error) error {
+		return errDefault
+	}, map[string]StepHandler{
+		"fetch_rows": func(interface{}, error) error { return nil },
+	})
+
+	var u *UnknownStepsError
+	if !errors.As(err, &u) || len(u.Steps) != 1 || u.Steps[0] != "fetch_rows" {
+		t.Fatalf("got %v, want UnknownStepsError for fetch_rows", err)
+	}
+}
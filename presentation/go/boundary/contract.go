@@ -0,0 +1,89 @@
+package boundary
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Condition is a predicate a step declares on its input (a pre-condition) or
+// its output (a post-condition). A violation fails the step like any other
+// error, so steps don't have to validate inline.
+type Condition struct {
+	Desc string
+
+	post  bool
+	typ   reflect.Type
+	holds func(interface{}) bool
+}
+
+// Requires returns a pre-condition: the step's input must satisfy holds.
+func Requires[T any](desc string, holds func(T) bool) Condition {
+	return condition(desc, false, holds)
+}
+
+// Ensures returns a post-condition: the step's output must satisfy holds.
+func Ensures[T any](desc string, holds func(T) bool) Condition {
+	return condition(desc, true, holds)
+}
+
+func condition[T any](desc string, post bool, holds func(T) bool) Condition {
+	return Condition{
+		Desc: desc,
+		post: post,
+		typ:  reflect.TypeOf((*T)(nil)).Elem(),
+		holds: func(v interface{}) bool {
+			t, ok := v.(T)
+			return ok && holds(t)
+		},
+	}
+}
+
+// check returns the first of conds, of the given kind, that v violates.
+func check(conds []Condition, post bool, v interface{}) error {
+	for _, c := range conds {
+		if c.post != post || c.holds(v) {
+			continue
+		}
+		if post {
+			return &PostConditionError{Condition: c.Desc, Value: v}
+		}
+		return &PreConditionError{Condition: c.Desc, Value: v}
+	}
+	return nil
+}
+
+// mustMatch panics if a condition's type isn't the step's input or output
+// type, so a mistyped contract fails when the Flow is built.
+func mustMatch(step string, conds []Condition, in, out reflect.Type) {
+	for _, c := range conds {
+		kind, want := "pre", in
+		if c.post {
+			kind, want = "post", out
+		}
+		if c.typ != want {
+			panic(fmt.Sprintf("boundary: %s: %s-condition %q takes %v, want %v", step, kind, c.Desc, c.typ, want))
+		}
+	}
+}
+
+// PreConditionError is the failure of a step whose input broke a
+// pre-condition.
+type PreConditionError struct {
+	Condition string
+	Value     interface{}
+}
+
+func (e *PreConditionError) Error() string {
+	return fmt.Sprintf("pre-condition failed: %s: got %#v", e.Condition, e.Value)
+}
+
+// PostConditionError is the failure of a step whose output broke a
+// post-condition.
+type PostConditionError struct {
+	Condition string
+	Value     interface{}
+}
+
+func (e *PostConditionError) Error() string {
+	return fmt.Sprintf("post-condition failed: %s: got %#v", e.Condition, e.Value)
+}
@@ -0,0 +1,72 @@
+package boundary
+
+import (
+	"errors"
+	"testing"
+)
+
+func positive(n int) bool {
+	return n > 0
+}
+
+func TestFlowAcceptsAPreCondition(t *testing.T) {
+	f := Start("double", double, Requires("positive", positive))
+
+	n, err := f.Run(2).OnError(testHandler)
+
+	if err != nil || n != 4 {
+		t.Fatalf("got %v, %v; want 4, nil", n, err)
+	}
+}
+
+func TestFlowReturnsAPreConditionError(t *testing.T) {
+	f := Start("double", double, Requires("positive", positive))
+
+	o := f.Run(-1)
+
+	var pre *PreConditionError
+	if o.Step != "double" || !errors.As(o.Err, &pre) || pre.Value != -1 {
+		t.Fatalf("got %q: %v; want a PreConditionError from double", o.Step, o.Err)
+	}
+}
+
+func TestFlowReturnsAPostConditionError(t *testing.T) {
+	f := Then(Start("itoa", itoa), "atoi", atoi, Requires("not empty", func(s string) bool {
+		return s != ""
+	}), Ensures("positive", positive))
+
+	o := f.Run(-1)
+
+	var pre *PreConditionError
+	var post *PostConditionError
+	if o.Step != "atoi" || errors.As(o.Err, &pre) || !errors.As(o.Err, &post) {
+		t.Fatalf("got %q: %v; want a PostConditionError from atoi", o.Step, o.Err)
+	}
+	if post.Condition != "positive" || post.Value != -1 {
+		t.Fatalf("got %+v", post)
+	}
+}
+
+func TestPreConditionsRouteThroughHandlers(t *testing.T) {
+	f := Then(Start("double", double), "custom_blow_up", itoa, Requires("small", func(n int) bool {
+		return n < 10
+	}))
+
+	_, err := f.Run(5).OnError(testHandler)
+
+	if err != errCustom {
+		t.Fatalf("got %v, want %v", err, errCustom)
+	}
+}
+
+func TestMistypedConditionsPanicWhenBuilt(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("want a panic")
+		}
+	}()
+
+	Start("double", double, Ensures("not empty", func(s string) bool {
+		return s != ""
+	}))
+}
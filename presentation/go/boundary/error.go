@@ -0,0 +1,108 @@
+package boundary
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// backtraceDepth is how many stack frames an Error keeps.
+const backtraceDepth = 5
+
+// Error is what a Handler hands back to callers. Users only ever see its
+// i18n key; engineers get the cause and where it was raised.
+type Error struct {
+	I18n  string
+	Err   error
+	Stack []string
+}
+
+// NewError wraps err under an i18n key, capturing the caller's stack.
+func NewError(err error, i18n string) *Error {
+	return &Error{I18n: i18n, Err: err, Stack: callers(3)}
+}
+
+func callers(skip int) []string {
+	pc := make([]uintptr, backtraceDepth)
+	n := runtime.Callers(skip, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	var stack []string
+	for {
+		f, more := frames.Next()
+		stack = append(stack, frame(f))
+		if !more {
+			return stack
+		}
+	}
+}
+
+// panicStack returns the stack of a panic being recovered, starting at the
+// function that panicked. Call it from the deferred function.
+func panicStack() []string {
+	pc := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(1, pc)])
+
+	var stack []string
+	panicking := false
+	for {
+		f, more := frames.Next()
+		if panicking && len(stack) < backtraceDepth {
+			stack = append(stack, frame(f))
+		}
+		if f.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+func frame(f runtime.Frame) string {
+	return fmt.Sprintf("%s:%d %s", f.File, f.Line, f.Function)
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.I18n
+	}
+	return e.I18n + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error with the same i18n key.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.I18n == e.I18n
+}
+
+// UserInfo returns what is safe to show an end user: the i18n key.
+func (e *Error) UserInfo() string {
+	return e.I18n
+}
+
+// SystemInfo is the detailed view of an Error, for logs and engineers.
+type SystemInfo struct {
+	Error     string   `json:"error"`
+	Backtrace []string `json:"backtrace"`
+	I18n      string   `json:"i18n"`
+	Trace     Trace    `json:"trace,omitempty"`
+}
+
+// SystemInfo returns the cause, its stack and the i18n key, plus the run's
+// trace when the cause is a step failure.
+func (e *Error) SystemInfo() SystemInfo {
+	info := SystemInfo{Backtrace: e.Stack, I18n: e.I18n}
+	if e.Err != nil {
+		info.Error = fmt.Sprintf("%T: %v", e.Err, e.Err)
+	}
+	var se *StepError
+	if errors.As(e.Err, &se) {
+		info.Trace = se.Trace
+	}
+	return info
+}
@@ -0,0 +1,45 @@
+package boundary
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestErrorShowsUsersOnlyTheKey(t *testing.T) {
+	err := NewError(io.ErrUnexpectedEOF, "invalid_date")
+
+	if err.UserInfo() != "invalid_date" {
+		t.Fatalf("got %q, want invalid_date", err.UserInfo())
+	}
+}
+
+func TestErrorShowsEngineersTheCauseAndStack(t *testing.T) {
+	info := NewError(io.ErrUnexpectedEOF, "invalid_date").SystemInfo()
+
+	if info.I18n != "invalid_date" || !strings.Contains(info.Error, "unexpected EOF") {
+		t.Fatalf("got %+v", info)
+	}
+	if len(info.Backtrace) == 0 || len(info.Backtrace) > backtraceDepth {
+		t.Fatalf("got %d frames, want 1 to %d", len(info.Backtrace), backtraceDepth)
+	}
+	if !strings.Contains(info.Backtrace[0], "TestErrorShowsEngineersTheCauseAndStack") {
+		t.Fatalf("got first frame %q, want the caller of NewError", info.Backtrace[0])
+	}
+}
+
+func TestErrorWorksWithIsAndAs(t *testing.T) {
+	var err error = &StepError{Step: "format_date", Err: NewError(io.ErrUnexpectedEOF, "invalid_date")}
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatal("want errors.Is to find the cause")
+	}
+	if !errors.Is(err, &Error{I18n: "invalid_date"}) {
+		t.Fatal("want errors.Is to match on the i18n key")
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.I18n != "invalid_date" {
+		t.Fatalf("got %v, want errors.As to find the *Error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package boundary
+
+import "reflect"
+
+// Flow is a Pipeline whose steps are type checked when it is built: each
+// step's input type must match the previous step's output type, so a Flow
+// takes an In and produces an Out.
+type Flow[In, Out any] struct {
+	p *Pipeline
+}
+
+// Start returns a Flow with a single step. It panics if a condition's type
+// doesn't match the step's input or output type.
+func Start[In, Out any](name string, fn func(In) (Out, error), conds ...Condition) *Flow[In, Out] {
+	return &Flow[In, Out]{p: New(typed(name, fn, conds))}
+}
+
+// Then returns a Flow that runs f and feeds its output to fn. f is left
+// unchanged. It panics if a condition's type doesn't match the step's input
+// or output type.
+func Then[In, Mid, Out any](f *Flow[In, Mid], name string, fn func(Mid) (Out, error), conds ...Condition) *Flow[In, Out] {
+	steps := make([]Step, len(f.p.steps), len(f.p.steps)+1)
+	copy(steps, f.p.steps)
+	return &Flow[In, Out]{p: New(append(steps, typed(name, fn, conds))...)}
+}
+
+func typed[In, Out any](name string, fn func(In) (Out, error), conds []Condition) Step {
+	mustMatch(name, conds, reflect.TypeOf((*In)(nil)).Elem(), reflect.TypeOf((*Out)(nil)).Elem())
+	return Step{Name: name, Conditions: conds, source: source(fn), Run: func(v interface{}) (interface{}, error) {
+		in, _ := v.(In)
+		return fn(in)
+	}}
+}
+
+// Steps returns the step names in the order they run.
+func (f *Flow[In, Out]) Steps() []string {
+	return f.p.Steps()
+}
+
+// Pipeline returns the untyped Pipeline f runs.
+func (f *Flow[In, Out]) Pipeline() *Pipeline {
+	return f.p
+}
+
+// Run feeds input through the steps, stopping at the first failure.
+func (f *Flow[In, Out]) Run(input In) *Outcome[Out] {
+	return &Outcome[Out]{Result: f.p.Run(input)}
+}
+
+// Outcome is the typed result of a Flow run.
+type Outcome[T any] struct {
+	*Result
+}
+
+// OnError hands a failure to h. A successful run returns the final value and
+// a nil error; a failed one returns the zero T and whatever h produced, or
+// the partial output of the last step if h accepted it.
+func (o *Outcome[T]) OnError(h Handler) (T, error) {
+	var zero T
+	v, err := o.Result.OnError(h)
+	if err != nil {
+		return zero, err
+	}
+	if _, ok := o.partial(); !o.Failed() || ok {
+		t, _ := v.(T)
+		return t, nil
+	}
+	return zero, nil
+}
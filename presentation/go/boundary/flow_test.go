@@ -0,0 +1,114 @@
+package boundary
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func double(n int) (int, error) {
+	return n * 2, nil
+}
+
+func itoa(n int) (string, error) {
+	return strconv.Itoa(n), nil
+}
+
+func atoi(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+func TestFlowChainsTypedSteps(t *testing.T) {
+	f := Then(Then(Start("double", double), "itoa", itoa), "atoi", atoi)
+
+	n, err := f.Run(21).OnError(testHandler)
+
+	if err != nil || n != 42 {
+		t.Fatalf("got %v, %v; want 42, nil", n, err)
+	}
+}
+
+func TestFlowCollectsTheFailingStep(t *testing.T) {
+	f := Then(Start("atoi", atoi), "double", double)
+
+	o := f.Run("not-a-number")
+
+	if o.Step != "atoi" || o.Value != "not-a-number" {
+		t.Fatalf("got step %q value %v; want atoi, not-a-number", o.Step, o.Value)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(o.Err, &numErr) {
+		t.Fatalf("got %v, want a *strconv.NumError", o.Err)
+	}
+}
+
+func TestFlowDispatchesOnStepName(t *testing.T) {
+	f := Then(Start("custom_blow_up", atoi), "double", double)
+
+	n, err := f.Run("x").OnError(testHandler)
+
+	if err != errCustom || n != 0 {
+		t.Fatalf("got %v, %v; want 0, %v", n, err, errCustom)
+	}
+}
+
+func TestThenLeavesTheFlowUnchanged(t *testing.T) {
+	base := Start("double", double)
+	Then(base, "itoa", itoa)
+
+	if len(base.Steps()) != 1 {
+		t.Fatalf("got steps %v, want [double]", base.Steps())
+	}
+}
+
+func TestFlowTracesEachStep(t *testing.T) {
+	f := Then(Then(Start("double", double), "itoa", itoa), "atoi", atoi)
+
+	o := f.Run(21)
+
+	if len(o.Trace) != 3 {
+		t.Fatalf("got %d entries, want 3", len(o.Trace))
+	}
+	e := o.Trace[1]
+	if e.Step != "itoa" || e.Input != 42 || e.Output != "42" {
+		t.Fatalf("got %+v", e)
+	}
+	if !strings.Contains(e.Source, "flow_test.go:") {
+		t.Fatalf("got source %q, want the step's definition", e.Source)
+	}
+}
+
+func TestFlowAttachesTheTraceToTheError(t *testing.T) {
+	f := Then(Then(Start("itoa", itoa), "double_atoi", func(s string) (int, error) {
+		return atoi(s + s + "x")
+	}), "double", double)
+
+	err := NewError(f.Run(4).Err, "default")
+
+	trace := err.SystemInfo().Trace
+	if len(trace) != 2 || trace[1].Step != "double_atoi" || trace[1].Input != "4" || trace[1].Err == "" {
+		t.Fatalf("got trace\n%s", trace)
+	}
+}
+
+func TestFlowReturnsAcceptedPartialOutput(t *testing.T) {
+	f := Then(Start("double", double), "halves", func(n int) ([]int, error) {
+		return []int{n / 2}, Partial([]int{n / 2}, errors.New("odd half dropped"))
+	})
+	accept := HandlerFunc(func(string, interface{}, error) error { return nil })
+
+	out := f.Run(2)
+	halves, err := out.OnError(accept)
+	if err != nil || len(halves) != 1 || halves[0] != 2 {
+		t.Fatalf("got %v, %v; want [2], nil", halves, err)
+	}
+	if err := out.Partial(); err == nil || err.Error() != "odd half dropped" {
+		t.Fatalf("got %v, want the partial failure", err)
+	}
+
+	halves, err = f.Run(2).OnError(testHandler)
+	if err != errDefault || halves != nil {
+		t.Fatalf("got %v, %v; want nil, %v", halves, err, errDefault)
+	}
+}
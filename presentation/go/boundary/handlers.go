@@ -0,0 +1,111 @@
+package boundary
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// DefaultI18n is the i18n key of a DefaultError.
+const DefaultI18n = "default"
+
+// StepHandler handles the failure of one step, given the input the step was
+// called with.
+type StepHandler func(input interface{}, err error) error
+
+// Handlers dispatches a failure to the handler registered under the failed
+// step's name, falling back to a default. Without a default, a failure no
+// handler is registered for yields a *DefaultError, so every failure comes
+// back as a well-formed error.
+type Handlers struct {
+	byStep map[string]StepHandler
+	def    StepHandler
+}
+
+// NewHandlers returns Handlers for a pipeline with the given step names. def
+// may be nil. It fails if byStep names a step the pipeline doesn't have.
+func NewHandlers(steps []string, def StepHandler, byStep map[string]StepHandler) (*Handlers, error) {
+	known := make(map[string]bool, len(steps))
+	for _, s := range steps {
+		known[s] = true
+	}
+
+	var unknown []string
+	for s := range byStep {
+		if !known[s] {
+			unknown = append(unknown, s)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, &UnknownStepsError{Steps: unknown}
+	}
+
+	return &Handlers{byStep: byStep, def: def}, nil
+}
+
+// Handle calls the handler registered for step, or the default.
+func (h *Handlers) Handle(step string, input interface{}, err error) error {
+	if f, ok := h.byStep[step]; ok {
+		return f(input, err)
+	}
+	if h.def == nil {
+		return &DefaultError{Step: step, Err: err, Stack: stack(err)}
+	}
+	return h.def(input, err)
+}
+
+// stack returns where err was raised: the panic site for a step that
+// panicked, or else the caller of Handle.
+func stack(err error) []string {
+	var p *PanicError
+	if errors.As(err, &p) && len(p.Stack) > 0 {
+		return p.Stack
+	}
+	return callers(4)
+}
+
+// DefaultError is returned for a failure that neither a step handler nor a
+// user default handled. It renders like an *Error with the DefaultI18n key.
+type DefaultError struct {
+	Step  string
+	Err   error
+	Stack []string
+}
+
+func (e *DefaultError) asError() *Error {
+	return &Error{I18n: DefaultI18n, Err: e.Err, Stack: e.Stack}
+}
+
+func (e *DefaultError) Error() string {
+	return e.asError().Error()
+}
+
+func (e *DefaultError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error with the DefaultI18n key.
+func (e *DefaultError) Is(target error) bool {
+	return e.asError().Is(target)
+}
+
+// UserInfo returns DefaultI18n.
+func (e *DefaultError) UserInfo() string {
+	return DefaultI18n
+}
+
+// SystemInfo returns the cause, its stack and the i18n key.
+func (e *DefaultError) SystemInfo() SystemInfo {
+	return e.asError().SystemInfo()
+}
+
+// UnknownStepsError lists handlers registered for steps a pipeline doesn't
+// have.
+type UnknownStepsError struct {
+	Steps []string
+}
+
+func (e *UnknownStepsError) Error() string {
+	return "boundary: handlers: no such steps: " + strings.Join(e.Steps, ", ")
+}
@@ -0,0 +1,52 @@
+package boundary
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Entry records one step of a run.
+type Entry struct {
+	Step     string        `json:"step"`
+	Source   string        `json:"source"`
+	Input    interface{}   `json:"input"`
+	Output   interface{}   `json:"output,omitempty"`
+	Err      string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Trace is the log of a run, one Entry per step that ran.
+type Trace []Entry
+
+// String renders the trace one step per block, with the values each step
+// saw, so a failure can be read without reproducing it.
+func (t Trace) String() string {
+	var b strings.Builder
+	for _, e := range t {
+		fmt.Fprintf(&b, "%s (%s) %v\n", e.Step, e.Source, e.Duration)
+		fmt.Fprintf(&b, "  input:  %#v\n", e.Input)
+		if e.Err != "" {
+			fmt.Fprintf(&b, "  error:  %s\n", e.Err)
+		} else {
+			fmt.Fprintf(&b, "  output: %#v\n", e.Output)
+		}
+	}
+	return b.String()
+}
+
+// source returns the file:line where fn is defined.
+func source(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return "unknown"
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	file, line := f.FileLine(f.Entry())
+	return fmt.Sprintf("%s:%d", file, line)
+}
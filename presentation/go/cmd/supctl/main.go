@@ -0,0 +1,68 @@
+// Command supctl inspects a running supervision tree served by
+// supervisor.Handler.
+//
+//	supctl tree [-addr url] [-json]
+//
+// prints the tree as text, or as JSON.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/fetch"
+)
+
+const defaultAddr = "http://localhost:6060/debug/supervisor"
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "tree" {
+		fmt.Fprintln(os.Stderr, "usage: supctl tree [-addr url] [-json]")
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("tree", flag.ExitOnError)
+	addr := flags.String("addr", defaultAddr, "url of the supervisor's handler")
+	asJSON := flags.Bool("json", false, "print the tree as JSON")
+	flags.Parse(os.Args[2:])
+
+	if err := tree(*addr, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// tree prints the supervision tree served at addr.
+func tree(addr string, asJSON bool) error {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return err
+	}
+	if !asJSON {
+		q := u.Query()
+		q.Set("format", "text")
+		u.RawQuery = q.Encode()
+	}
+
+	client := fetch.New(fetch.Config{Timeout: 5 * time.Second})
+	body, err := client.Get(context.Background(), u.String())
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		var out bytes.Buffer
+		if err := json.Indent(&out, body, "", "  "); err != nil {
+			return err
+		}
+		body = append(out.Bytes(), '\n')
+	}
+	_, err = os.Stdout.Write(body)
+	return err
+}
@@ -0,0 +1,138 @@
+// Package config loads the Rescuetime settings from the environment at
+// startup, so misconfiguration fails at boot rather than on the first
+// request.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/boundary"
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/fetch"
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/rescuetime"
+)
+
+// InvalidConfig is the i18n key of the error Load returns.
+const InvalidConfig = "invalid_config"
+
+// The environment variables Load reads.
+const (
+	APIURLVar   = "RESCUETIME_API_URL"
+	APIKeyVar   = "RESCUETIME_API_KEY"
+	TimezoneVar = "RESCUETIME_TIMEZONE"
+	TimeoutVar  = "RESCUETIME_TIMEOUT"
+)
+
+// Config is the validated Rescuetime configuration.
+type Config struct {
+	APIURL   *url.URL
+	APIKey   string
+	Timezone *time.Location
+	Timeout  time.Duration
+}
+
+// Load reads Config from the environment.
+func Load() (*Config, error) {
+	return LoadFrom(os.LookupEnv)
+}
+
+// LoadFrom reads Config through lookup. Every missing or invalid variable
+// is reported at once, in a *boundary.Error wrapping an *Error.
+func LoadFrom(lookup func(string) (string, bool)) (*Config, error) {
+	var (
+		c    = &Config{Timeout: fetch.DefaultTimeout}
+		errs Error
+	)
+
+	if v, ok := required(lookup, APIURLVar, &errs); ok {
+		u, err := url.Parse(v)
+		switch {
+		case err != nil:
+			errs.add(APIURLVar, v, err.Error())
+		case u.Scheme != "http" && u.Scheme != "https", u.Host == "":
+			errs.add(APIURLVar, v, "want an absolute http or https url")
+		default:
+			c.APIURL = u
+		}
+	}
+
+	if v, ok := required(lookup, APIKeyVar, &errs); ok {
+		c.APIKey = v
+	}
+
+	if v, ok := required(lookup, TimezoneVar, &errs); ok {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			errs.add(TimezoneVar, v, "not in the tz database")
+		} else {
+			c.Timezone = loc
+		}
+	}
+
+	if v, ok := lookup(TimeoutVar); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			errs.add(TimeoutVar, v, "want a positive duration, such as 5s")
+		} else {
+			c.Timeout = d
+		}
+	}
+
+	if len(errs.Fields) > 0 {
+		return nil, boundary.NewError(&errs, InvalidConfig)
+	}
+	return c, nil
+}
+
+func required(lookup func(string) (string, bool), name string, errs *Error) (string, bool) {
+	v, ok := lookup(name)
+	if !ok || strings.TrimSpace(v) == "" {
+		errs.add(name, "", "missing")
+		return "", false
+	}
+	return v, true
+}
+
+// Rescuetime returns the settings for a rescuetime.Consumer.
+func (c *Config) Rescuetime() rescuetime.Config {
+	return rescuetime.Config{
+		APIURL:   c.APIURL.String(),
+		APIKey:   c.APIKey,
+		Timezone: c.Timezone.String(),
+		Fetch:    fetch.Config{Timeout: c.Timeout},
+	}
+}
+
+// FieldError describes one missing or invalid variable.
+type FieldError struct {
+	Var    string
+	Value  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return e.Var + ": " + e.Reason
+	}
+	return fmt.Sprintf("%s: %q: %s", e.Var, e.Value, e.Reason)
+}
+
+// Error lists every problem Load found.
+type Error struct {
+	Fields []*FieldError
+}
+
+func (e *Error) add(name, value, reason string) {
+	e.Fields = append(e.Fields, &FieldError{Var: name, Value: value, Reason: reason})
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "config: " + strings.Join(msgs, "; ")
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/boundary"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestLoadsTheEnvironment(t *testing.T) {
+	c, err := LoadFrom(env(map[string]string{
+		APIURLVar:   "https://www.rescuetime.com/anapi/data",
+		APIKeyVar:   "8sdnjf7sdnf0",
+		TimezoneVar: "America/Chicago",
+		TimeoutVar:  "2s",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.APIURL.Host != "www.rescuetime.com" || c.APIKey != "8sdnjf7sdnf0" ||
+		c.Timezone.String() != "America/Chicago" || c.Timeout != 2*time.Second {
+		t.Fatalf("got %+v", c)
+	}
+	if r := c.Rescuetime(); r.Timezone != "America/Chicago" || r.Fetch.Timeout != 2*time.Second {
+		t.Fatalf("got %+v", r)
+	}
+}
+
+func TestReportsEveryProblemAtOnce(t *testing.T) {
+	_, err := LoadFrom(env(map[string]string{
+		APIURLVar:   "someapi.com",
+		TimezoneVar: "America/Nowhere",
+		TimeoutVar:  "soon",
+	}))
+
+	if !errors.Is(err, &boundary.Error{I18n: InvalidConfig}) {
+		t.Fatalf("got %v, want %s", err, InvalidConfig)
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("got %v, want a *config.Error", err)
+	}
+	var got []string
+	for _, f := range e.Fields {
+		got = append(got, f.Var)
+	}
+	want := []string{APIURLVar, APIKeyVar, TimezoneVar, TimeoutVar}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -2,21 +2,37 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"os"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/config"
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/rescuetime"
 )
 
 func main() {
-	client := http.Client{
-		Timeout: 1,
+	c, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	consumer, err := rescuetime.NewConsumer(c.Rescuetime())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	date := time.Now().Format("2006-01-02")
+	if len(os.Args) > 1 {
+		date = os.Args[1]
 	}
-	response, err := client.Get("http://blah.lskdfj")
+	rows, err := consumer.Get(date)
 
 	if err != nil {
 		fmt.Println(err)
-		return
 	}
 
-	contents, err := ioutil.ReadAll(response.Body)
-	fmt.Println(string(contents))
+	for _, row := range rows {
+		fmt.Printf("%+v\n", row)
+	}
 }
@@ -0,0 +1,141 @@
+package fetch
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// classify turns an error from http.Client.Do into one of the typed errors
+// below. timeout is the deadline the request had.
+func classify(url string, timeout time.Duration, err error) error {
+	var (
+		netErr       net.Error
+		dnsErr       *net.DNSError
+		verifyErr    *tls.CertificateVerificationError
+		recordErr    tls.RecordHeaderError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return &TimeoutError{URL: url, Timeout: timeout, Err: err}
+	case errors.As(err, &dnsErr):
+		return &DNSError{URL: url, Err: dnsErr}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return &ConnRefusedError{URL: url, Err: err}
+	case errors.As(err, &verifyErr),
+		errors.As(err, &recordErr),
+		errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr):
+		return &TLSError{URL: url, Err: err}
+	}
+	return &RequestError{URL: url, Err: err}
+}
+
+// TimeoutError is returned when a request outlives its timeout: the
+// Config's, or the caller's context deadline if that came first.
+type TimeoutError struct {
+	URL     string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("fetch: http timeout: the url of %s timed out at %g seconds", e.URL, e.Timeout.Round(time.Millisecond).Seconds())
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// DNSError is returned when the URL's host can't be resolved.
+type DNSError struct {
+	URL string
+	Err error
+}
+
+func (e *DNSError) Error() string {
+	return fmt.Sprintf("fetch: dns failure: the host of %s could not be resolved: %v", e.URL, e.Err)
+}
+
+func (e *DNSError) Unwrap() error { return e.Err }
+
+// ConnRefusedError is returned when nothing is listening at the URL.
+type ConnRefusedError struct {
+	URL string
+	Err error
+}
+
+func (e *ConnRefusedError) Error() string {
+	return fmt.Sprintf("fetch: connection refused: the url of %s refused the connection", e.URL)
+}
+
+func (e *ConnRefusedError) Unwrap() error { return e.Err }
+
+// TLSError is returned when the TLS handshake or certificate check fails.
+type TLSError struct {
+	URL string
+	Err error
+}
+
+func (e *TLSError) Error() string {
+	return fmt.Sprintf("fetch: tls failure: the url of %s failed the handshake: %v", e.URL, e.Err)
+}
+
+func (e *TLSError) Unwrap() error { return e.Err }
+
+// StatusError is returned for a response outside the 2xx range. Body holds
+// the start of the response body.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("fetch: http status: the url of %s returned %d %s: %q", e.URL, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// BodyTooLargeError is returned when a response body exceeds the Config's
+// MaxBodySize.
+type BodyTooLargeError struct {
+	URL   string
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("fetch: body read: the url of %s returned more than %d bytes", e.URL, e.Limit)
+}
+
+// ReadError is returned when the response body can't be read.
+type ReadError struct {
+	URL string
+	Err error
+}
+
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("fetch: body read: the url of %s failed mid-body: %v", e.URL, e.Err)
+}
+
+func (e *ReadError) Unwrap() error { return e.Err }
+
+// RequestError is returned for any other failure to make the request.
+type RequestError struct {
+	URL string
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("fetch: request: the url of %s could not be requested: %v", e.URL, e.Err)
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
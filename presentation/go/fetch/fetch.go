@@ -0,0 +1,140 @@
+// Package fetch makes HTTP GET requests and classifies what went wrong, so
+// callers can tell a DNS failure from a timeout from a bad status.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Defaults used when a Config leaves a field unset.
+const (
+	DefaultTimeout     = 5 * time.Second
+	DefaultMaxBodySize = 10 << 20
+)
+
+// excerptSize is how much of an error response's body a StatusError keeps.
+const excerptSize = 512
+
+// drainSize is how much of an unread body Get discards so the connection
+// can be reused. Bodies with more left are closed instead.
+const drainSize = 4 << 10
+
+// Config configures a Client.
+type Config struct {
+	// Timeout bounds each request, from dialing to reading the body.
+	Timeout time.Duration
+
+	// MaxBodySize is the most bytes of a response body Get will read.
+	MaxBodySize int64
+}
+
+// Client fetches URLs with the timeout from its Config.
+type Client struct {
+	http   *http.Client
+	config Config
+}
+
+// New returns a Client for c.
+func New(c Config) *Client {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.MaxBodySize <= 0 {
+		c.MaxBodySize = DefaultMaxBodySize
+	}
+	return &Client{http: &http.Client{}, config: c}
+}
+
+// Get requests rawurl and returns the response body. Failures are one of
+// *TimeoutError, *DNSError, *ConnRefusedError, *TLSError, *StatusError,
+// *BodyTooLargeError, *ReadError or *RequestError, and never include the
+// URL's key.
+func (c *Client) Get(ctx context.Context, rawurl string) ([]byte, error) {
+	timeout := c.config.Timeout
+	if d, ok := ctx.Deadline(); ok && time.Until(d) < timeout {
+		timeout = time.Until(d)
+		if timeout < 0 {
+			timeout = 0
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	u := Redact(rawurl)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
+	if err != nil {
+		return nil, &RequestError{URL: u, Err: redactErr(err)}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, classify(u, timeout, redactErr(err))
+	}
+	defer func() {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, drainSize))
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, excerptSize+1))
+		return nil, &StatusError{URL: u, StatusCode: resp.StatusCode, Body: truncate(excerpt)}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.config.MaxBodySize+1))
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &TimeoutError{URL: u, Timeout: timeout, Err: ctx.Err()}
+		}
+		return nil, &ReadError{URL: u, Err: err}
+	}
+	if int64(len(body)) > c.config.MaxBodySize {
+		return nil, &BodyTooLargeError{URL: u, Limit: c.config.MaxBodySize}
+	}
+	return body, nil
+}
+
+func truncate(b []byte) string {
+	if len(b) > excerptSize {
+		return string(b[:excerptSize]) + "..."
+	}
+	return string(b)
+}
+
+// secrets are the query parameters Redact hides.
+var secrets = []string{"key", "api_key", "token", "password"}
+
+// Redact returns rawurl with the values of secret query parameters, such as
+// key, replaced.
+func Redact(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "(unparseable url)"
+	}
+	q := u.Query()
+	redacted := false
+	for _, k := range secrets {
+		if q.Has(k) {
+			q.Set(k, "REDACTED")
+			redacted = true
+		}
+	}
+	if redacted {
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// redactErr redacts the URL that net/http puts in its errors.
+func redactErr(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		ue.URL = Redact(ue.URL)
+	}
+	return err
+}
@@ -0,0 +1,186 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetReturnsTheBody(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer s.Close()
+
+	body, err := New(Config{}).Get(context.Background(), s.URL)
+
+	if err != nil || string(body) != `{"rows":[]}` {
+		t.Fatalf("got %q, %v", body, err)
+	}
+}
+
+func TestGetClassifiesTimeouts(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	_, err := New(Config{Timeout: 10 * time.Millisecond}).Get(context.Background(), s.URL)
+
+	var e *TimeoutError
+	if !errors.As(err, &e) || e.Timeout != 10*time.Millisecond {
+		t.Fatalf("got %v, want a TimeoutError", err)
+	}
+	want := "fetch: http timeout: the url of " + s.URL + " timed out at 0.01 seconds"
+	if err.Error() != want {
+		t.Fatalf("got %q, want %q", err, want)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want it to wrap %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestGetReportsTheCallersEarlierDeadline(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := New(Config{Timeout: time.Minute}).Get(ctx, s.URL)
+
+	var e *TimeoutError
+	if !errors.As(err, &e) || e.Timeout <= 0 || e.Timeout > 20*time.Millisecond || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a TimeoutError at the context's deadline", err)
+	}
+}
+
+func TestGetReportsPassedDeadlinesAsZero(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err := New(Config{}).Get(ctx, "http://127.0.0.1:1")
+
+	var e *TimeoutError
+	if !errors.As(err, &e) || e.Timeout != 0 || !strings.HasSuffix(err.Error(), "timed out at 0 seconds") {
+		t.Fatalf("got %v, want a TimeoutError at 0 seconds", err)
+	}
+}
+
+func TestGetClassifiesDNSFailures(t *testing.T) {
+	_, err := New(Config{}).Get(context.Background(), "http://rescuetime.invalid/api?key=secret")
+
+	var e *DNSError
+	if !errors.As(err, &e) {
+		t.Fatalf("got %v, want a DNSError", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Fatalf("got %q, want the key redacted", err)
+	}
+}
+
+func TestGetClassifiesRefusedConnections(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	_, err = New(Config{}).Get(context.Background(), "http://"+addr)
+
+	var e *ConnRefusedError
+	if !errors.As(err, &e) {
+		t.Fatalf("got %v, want a ConnRefusedError", err)
+	}
+}
+
+func TestGetClassifiesTLSFailures(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	_, err := New(Config{}).Get(context.Background(), s.URL)
+
+	var e *TLSError
+	if !errors.As(err, &e) {
+		t.Fatalf("got %v, want a TLSError", err)
+	}
+}
+
+func TestGetClassifiesBadStatuses(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"# key not found"}`))
+	}))
+	defer s.Close()
+
+	_, err := New(Config{}).Get(context.Background(), s.URL+"?key=8sdnjf7sdnf0&format=json")
+
+	var e *StatusError
+	if !errors.As(err, &e) || e.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got %v, want a 401 StatusError", err)
+	}
+	if e.URL != s.URL+"?format=json&key=REDACTED" {
+		t.Fatalf("got url %q, want the key redacted", e.URL)
+	}
+	if e.Body != `{"error":"# key not found"}` {
+		t.Fatalf("got body %q", e.Body)
+	}
+}
+
+func TestGetTruncatesErrorBodies(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(strings.Repeat("x", 4*excerptSize)))
+	}))
+	defer s.Close()
+
+	_, err := New(Config{}).Get(context.Background(), s.URL)
+
+	var e *StatusError
+	if !errors.As(err, &e) || len(e.Body) != excerptSize+len("...") {
+		t.Fatalf("got %v, want a truncated body", err)
+	}
+}
+
+func TestGetEnforcesMaxBodySize(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer s.Close()
+
+	_, err := New(Config{MaxBodySize: 5}).Get(context.Background(), s.URL)
+
+	var e *BodyTooLargeError
+	if !errors.As(err, &e) || e.Limit != 5 {
+		t.Fatalf("got %v, want a BodyTooLargeError", err)
+	}
+}
+
+func TestErrorsNeverIncludeTheKey(t *testing.T) {
+	_, err := New(Config{}).Get(context.Background(), "http://127.0.0.1:0/?key=8sdnjf7sdnf0")
+
+	if err == nil || strings.Contains(err.Error(), "8sdnjf7sdnf0") {
+		t.Fatalf("got %v, want the key redacted", err)
+	}
+}
+
+func TestGetClassifiesBodyReadFailures(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("short"))
+	}))
+	defer s.Close()
+
+	_, err := New(Config{}).Get(context.Background(), s.URL)
+
+	var e *ReadError
+	if !errors.As(err, &e) {
+		t.Fatalf("got %v, want a ReadError", err)
+	}
+}
@@ -0,0 +1,229 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Mailbox errors.
+var (
+	ErrMailboxFull = errors.New("mailbox full")
+	ErrClosed      = errors.New("mailbox closed")
+	ErrTimeout     = errors.New("receive timed out")
+	ErrNotRunning  = errors.New("actor not running")
+)
+
+// Forever, as the after of Mailbox.Receive, waits for a message however
+// long it takes.
+const Forever time.Duration = -1
+
+// Overflow says what sending to a full Mailbox does.
+type Overflow int
+
+const (
+	// Block waits for space.
+	Block Overflow = iota
+
+	// Drop discards the message.
+	Drop
+
+	// FailFast returns ErrMailboxFull.
+	FailFast
+)
+
+// Mailbox is a bounded queue of messages of type M that can be received
+// selectively, like an Erlang receive.
+type Mailbox[M any] struct {
+	size     int
+	overflow Overflow
+
+	mu      sync.Mutex
+	msgs    []M
+	dropped int
+	closed  bool
+	changed chan struct{}
+
+	// health, if set, is the channel of the process receiving from the
+	// mailbox, so Receive answers Ping for it.
+	health <-chan healthCheck
+}
+
+// NewMailbox returns a Mailbox that holds up to size messages.
+func NewMailbox[M any](size int, overflow Overflow) *Mailbox[M] {
+	if size < 1 {
+		size = 1
+	}
+	return &Mailbox[M]{size: size, overflow: overflow, changed: make(chan struct{})}
+}
+
+// broadcast wakes everyone waiting on a change. m.mu must be held.
+func (m *Mailbox[M]) broadcast() {
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+// Send puts msg in the mailbox, applying its Overflow policy when full.
+func (m *Mailbox[M]) Send(ctx context.Context, msg M) error {
+	m.mu.Lock()
+	for {
+		if m.closed {
+			m.mu.Unlock()
+			return ErrClosed
+		}
+		if len(m.msgs) < m.size {
+			m.msgs = append(m.msgs, msg)
+			m.broadcast()
+			m.mu.Unlock()
+			return nil
+		}
+
+		switch m.overflow {
+		case Drop:
+			m.dropped++
+			m.mu.Unlock()
+			return nil
+		case FailFast:
+			m.mu.Unlock()
+			return ErrMailboxFull
+		}
+
+		changed := m.changed
+		m.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		m.mu.Lock()
+	}
+}
+
+// Receive removes and returns the oldest message that match accepts,
+// leaving the others in order. A nil match accepts any message. If none
+// arrives within after it returns ErrTimeout; an after of 0 only checks
+// the messages already there, and Forever never times out. Receive
+// answers health checks for the process of the Actor it runs on.
+func (m *Mailbox[M]) Receive(ctx context.Context, after time.Duration, match func(M) bool) (M, error) {
+	select {
+	case hc := <-m.health:
+		hc.answer()
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if after > 0 {
+		t := time.NewTimer(after)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	var zero M
+	m.mu.Lock()
+	for {
+		for i, msg := range m.msgs {
+			if match == nil || match(msg) {
+				m.msgs = append(m.msgs[:i], m.msgs[i+1:]...)
+				m.broadcast()
+				m.mu.Unlock()
+				return msg, nil
+			}
+		}
+		if m.closed {
+			m.mu.Unlock()
+			return zero, ErrClosed
+		}
+		if after == 0 {
+			m.mu.Unlock()
+			return zero, ErrTimeout
+		}
+
+		changed := m.changed
+		m.mu.Unlock()
+		select {
+		case <-changed:
+		case hc := <-m.health:
+			hc.answer()
+		case <-timeout:
+			return zero, ErrTimeout
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+		m.mu.Lock()
+	}
+}
+
+// Dropped returns how many messages the Drop policy has discarded.
+func (m *Mailbox[M]) Dropped() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dropped
+}
+
+// Close makes further sends and receives fail with ErrClosed, once the
+// messages already there are received.
+func (m *Mailbox[M]) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.closed {
+		m.closed = true
+		m.broadcast()
+	}
+}
+
+// Actor runs a behavior over a Mailbox of M. Its Run method is a
+// supervisor.Worker: each run gets a fresh mailbox, so an actor restarted
+// after a crash doesn't see the messages that crashed it. The mailbox
+// answers health checks for the process Run runs in, so a supervised actor
+// registered by name answers Ping while its behavior receives.
+type Actor[M any] struct {
+	size     int
+	overflow Overflow
+	behavior func(ctx context.Context, inbox *Mailbox[M]) error
+
+	mu    sync.Mutex
+	inbox *Mailbox[M]
+}
+
+// NewActor returns an Actor whose mailboxes hold size messages.
+func NewActor[M any](size int, overflow Overflow, behavior func(ctx context.Context, inbox *Mailbox[M]) error) *Actor[M] {
+	return &Actor[M]{size: size, overflow: overflow, behavior: behavior}
+}
+
+// Run runs the behavior with a fresh mailbox until it returns.
+func (a *Actor[M]) Run(ctx context.Context) error {
+	inbox := NewMailbox[M](a.size, a.overflow)
+	if p, ok := Self(ctx); ok {
+		inbox.health = p.health
+	}
+	a.mu.Lock()
+	a.inbox = inbox
+	a.mu.Unlock()
+
+	defer func() {
+		inbox.Close()
+		a.mu.Lock()
+		if a.inbox == inbox {
+			a.inbox = nil
+		}
+		a.mu.Unlock()
+	}()
+	return a.behavior(ctx, inbox)
+}
+
+// Send sends msg to the running actor's mailbox. It returns ErrNotRunning
+// between runs.
+func (a *Actor[M]) Send(ctx context.Context, msg M) error {
+	a.mu.Lock()
+	inbox := a.inbox
+	a.mu.Unlock()
+
+	if inbox == nil {
+		return ErrNotRunning
+	}
+	if err := inbox.Send(ctx, msg); err != ErrClosed {
+		return err
+	}
+	return ErrNotRunning
+}
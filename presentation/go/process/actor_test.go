@@ -0,0 +1,101 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReceiveSelectsTheFirstMatch(t *testing.T) {
+	m := NewMailbox[int](5, FailFast)
+	for _, n := range []int{1, 2, 3, 4} {
+		m.Send(context.Background(), n)
+	}
+
+	even := func(n int) bool { return n%2 == 0 }
+	if n, err := m.Receive(context.Background(), 0, even); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2", n, err)
+	}
+	for _, want := range []int{1, 3, 4} {
+		if n, _ := m.Receive(context.Background(), 0, nil); n != want {
+			t.Fatalf("got %d, want %d", n, want)
+		}
+	}
+}
+
+func TestReceiveTimesOutAfter(t *testing.T) {
+	m := NewMailbox[string](1, Block)
+	m.Send(context.Background(), "skipped")
+
+	_, err := m.Receive(context.Background(), 10*time.Millisecond, func(s string) bool { return s == "wanted" })
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v, want %v", err, ErrTimeout)
+	}
+	if _, err := m.Receive(context.Background(), 0, nil); err != nil {
+		t.Fatal("want the skipped message left in the mailbox")
+	}
+}
+
+func TestFullMailboxes(t *testing.T) {
+	ctx := context.Background()
+
+	failing := NewMailbox[int](1, FailFast)
+	failing.Send(ctx, 1)
+	if err := failing.Send(ctx, 2); !errors.Is(err, ErrMailboxFull) {
+		t.Fatalf("got %v, want %v", err, ErrMailboxFull)
+	}
+
+	dropping := NewMailbox[int](1, Drop)
+	dropping.Send(ctx, 1)
+	if err := dropping.Send(ctx, 2); err != nil || dropping.Dropped() != 1 {
+		t.Fatalf("got %v with %d dropped, want the message dropped", err, dropping.Dropped())
+	}
+
+	blocking := NewMailbox[int](1, Block)
+	blocking.Send(ctx, 1)
+	sent := make(chan error)
+	go func() { sent <- blocking.Send(ctx, 2) }()
+	select {
+	case <-sent:
+		t.Fatal("want the send blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+	blocking.Receive(ctx, 0, nil)
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := blocking.Send(short, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestActorsGetAFreshMailboxEachRun(t *testing.T) {
+	got := make(chan string)
+	a := NewActor(2, FailFast, func(ctx context.Context, inbox *Mailbox[string]) error {
+		msg, err := inbox.Receive(ctx, Forever, nil)
+		if err != nil {
+			return err
+		}
+		got <- msg
+		return errors.New("woops!")
+	})
+	if err := a.Send(context.Background(), "early"); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("got %v, want %v", err, ErrNotRunning)
+	}
+
+	for _, msg := range []string{"first", "second"} {
+		done := make(chan error)
+		go func() { done <- a.Run(context.Background()) }()
+		for a.Send(context.Background(), msg) == ErrNotRunning {
+			time.Sleep(time.Millisecond)
+		}
+		if m := <-got; m != msg {
+			t.Fatalf("got %q, want %q", m, msg)
+		}
+		<-done
+	}
+}
@@ -0,0 +1,367 @@
+// Package process gives goroutines the Erlang process primitives the essay
+// describes: a mailbox, links that take linked processes down together,
+// monitors that report a process's exit, and trap_exit, which turns exit
+// signals from links into messages.
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Exit reasons with a meaning of their own.
+var (
+	// Normal is the reason of a process whose function returned nil. It
+	// doesn't take linked processes down.
+	Normal = errors.New("normal")
+
+	// Shutdown is the reason of a process whose context was cancelled
+	// without an exit signal.
+	Shutdown = errors.New("shutdown")
+
+	// Killed is the reason Kill uses; it can't be trapped.
+	Killed = errors.New("killed")
+
+	// NoProc is the reason reported for linking to or monitoring a process
+	// that has already exited.
+	NoProc = errors.New("noproc")
+)
+
+// PID identifies a process.
+type PID uint64
+
+func (p PID) String() string {
+	return fmt.Sprintf("<0.%d.0>", uint64(p))
+}
+
+var lastPID uint64
+
+// Ref identifies a monitor.
+type Ref uint64
+
+var lastRef uint64
+
+// Exit is the message a process trapping exits receives when a linked
+// process exits.
+type Exit struct {
+	PID    PID
+	Reason error
+}
+
+// Down is the message a monitoring process receives when the monitored
+// process exits.
+type Down struct {
+	Ref    Ref
+	PID    PID
+	Reason error
+}
+
+// PanicError is the reason of a process whose function panicked.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Process is a goroutine with a mailbox, links and monitors.
+type Process struct {
+	pid    PID
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	inbox  mailbox
+	health chan healthCheck
+	done   chan struct{}
+	reason error
+
+	mu       sync.Mutex
+	exited   bool
+	trap     bool
+	links    map[*Process]bool
+	monitors map[Ref]*Process
+}
+
+type selfKey struct{}
+
+// Spawn runs fn in a new process. The process's context is derived from
+// ctx; fn should return once it is done.
+func Spawn(ctx context.Context, fn func(p *Process) error) *Process {
+	p := newProcess(ctx)
+	go p.run(fn)
+	return p
+}
+
+// SpawnLink is Spawn, with the new process linked to parent before fn runs.
+// The new process's context keeps parent's values but not its
+// cancellation: parent's exit reaches it only through the link.
+func SpawnLink(parent *Process, fn func(p *Process) error) *Process {
+	p := newProcess(context.WithoutCancel(parent.ctx))
+	parent.Link(p)
+	go p.run(fn)
+	return p
+}
+
+func newProcess(ctx context.Context) *Process {
+	p := &Process{
+		pid:      PID(atomic.AddUint64(&lastPID, 1)),
+		inbox:    newMailbox(),
+		health:   make(chan healthCheck),
+		done:     make(chan struct{}),
+		links:    map[*Process]bool{},
+		monitors: map[Ref]*Process{},
+	}
+	p.ctx, p.cancel = context.WithCancelCause(context.WithValue(ctx, selfKey{}, p))
+	return p
+}
+
+// Self returns the process whose context ctx is, or derives from.
+func Self(ctx context.Context) (*Process, bool) {
+	p, ok := ctx.Value(selfKey{}).(*Process)
+	return p, ok
+}
+
+func (p *Process) run(fn func(p *Process) error) {
+	p.exit(p.call(fn))
+}
+
+// call runs fn and turns how it stopped into an exit reason.
+func (p *Process) call(fn func(p *Process) error) (reason error) {
+	defer func() {
+		if v := recover(); v != nil {
+			reason = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	err := fn(p)
+	if p.ctx.Err() != nil && (err == nil || errors.Is(err, context.Canceled)) {
+		return p.cause()
+	}
+	if err == nil {
+		return Normal
+	}
+	return err
+}
+
+func (p *Process) cause() error {
+	cause := context.Cause(p.ctx)
+	if cause == context.Canceled || cause == context.DeadlineExceeded {
+		return Shutdown
+	}
+	return cause
+}
+
+// exit records reason, signals links and notifies monitors.
+func (p *Process) exit(reason error) {
+	p.mu.Lock()
+	p.exited = true
+	p.reason = reason
+	links, monitors := p.links, p.monitors
+	p.links, p.monitors = nil, nil
+	p.mu.Unlock()
+
+	p.cancel(reason)
+	close(p.done)
+	unregisterAll(p)
+
+	for l := range links {
+		l.mu.Lock()
+		delete(l.links, p)
+		l.mu.Unlock()
+		l.signal(p.pid, reason)
+	}
+	for ref, watcher := range monitors {
+		watcher.Send(Down{Ref: ref, PID: p.pid, Reason: reason})
+	}
+}
+
+// signal delivers an exit signal from a linked process: as an Exit message
+// if p traps exits, otherwise by taking p down with the same reason unless
+// that reason is Normal.
+func (p *Process) signal(from PID, reason error) {
+	p.mu.Lock()
+	trap := p.trap
+	p.mu.Unlock()
+
+	switch {
+	case trap:
+		p.Send(Exit{PID: from, Reason: reason})
+	case reason != Normal:
+		p.cancel(reason)
+	}
+}
+
+// PID returns p's PID.
+func (p *Process) PID() PID {
+	return p.pid
+}
+
+// Context returns p's context. It is cancelled when p is taken down by a
+// link or Kill; its cause is the exit reason.
+func (p *Process) Context() context.Context {
+	return p.ctx
+}
+
+// Done is closed when p has exited.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits for p to exit and returns its reason.
+func (p *Process) Wait() error {
+	<-p.done
+	return p.reason
+}
+
+// Alive reports whether p is still running.
+func (p *Process) Alive() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// TrapExit sets whether exit signals from links reach p as Exit messages
+// instead of taking it down.
+func (p *Process) TrapExit(trap bool) {
+	p.mu.Lock()
+	p.trap = trap
+	p.mu.Unlock()
+}
+
+// Kill takes p down with reason Killed, whether or not it traps exits.
+func (p *Process) Kill() {
+	p.cancel(Killed)
+}
+
+// Link links p and other: when either exits, the other gets an exit signal.
+// Linking to a process that has exited signals p with NoProc.
+func (p *Process) Link(other *Process) {
+	if p == other {
+		return
+	}
+	first, second := p, other
+	if second.pid < first.pid {
+		first, second = second, first
+	}
+	first.mu.Lock()
+	second.mu.Lock()
+	gone := other.exited
+	if !p.exited && !gone {
+		p.links[other] = true
+		other.links[p] = true
+	}
+	second.mu.Unlock()
+	first.mu.Unlock()
+
+	if gone {
+		p.signal(other.pid, NoProc)
+	}
+}
+
+// Unlink removes a link between p and other.
+func (p *Process) Unlink(other *Process) {
+	p.mu.Lock()
+	delete(p.links, other)
+	p.mu.Unlock()
+	other.mu.Lock()
+	delete(other.links, p)
+	other.mu.Unlock()
+}
+
+// Monitor makes p receive a Down message when other exits. Monitoring a
+// process that has exited sends the Down at once, with NoProc.
+func (p *Process) Monitor(other *Process) Ref {
+	ref := Ref(atomic.AddUint64(&lastRef, 1))
+	other.mu.Lock()
+	exited := other.exited
+	if !exited {
+		other.monitors[ref] = p
+	}
+	other.mu.Unlock()
+
+	if exited {
+		p.Send(Down{Ref: ref, PID: other.pid, Reason: NoProc})
+	}
+	return ref
+}
+
+// Demonitor removes the monitor ref that p holds on other.
+func (p *Process) Demonitor(other *Process, ref Ref) {
+	other.mu.Lock()
+	delete(other.monitors, ref)
+	other.mu.Unlock()
+}
+
+// Send puts msg in p's mailbox. Messages to a process that has exited are
+// dropped.
+func (p *Process) Send(msg interface{}) {
+	if p.Alive() {
+		p.inbox.put(msg)
+	}
+}
+
+// Receive returns the next message in p's mailbox, answering any health
+// checks on the way. It returns an error once ctx or p's context is
+// done.
+func (p *Process) Receive(ctx context.Context) (interface{}, error) {
+	return p.inbox.take(ctx, p.ctx, p.health)
+}
+
+// mailbox is an unbounded FIFO queue.
+type mailbox struct {
+	mu     sync.Mutex
+	msgs   []interface{}
+	notify chan struct{}
+}
+
+func newMailbox() mailbox {
+	return mailbox{notify: make(chan struct{}, 1)}
+}
+
+func (m *mailbox) put(msg interface{}) {
+	m.mu.Lock()
+	m.msgs = append(m.msgs, msg)
+	m.mu.Unlock()
+
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (m *mailbox) take(ctx, own context.Context, health <-chan healthCheck) (interface{}, error) {
+	for {
+		select {
+		case hc := <-health:
+			hc.answer()
+		default:
+		}
+
+		m.mu.Lock()
+		if len(m.msgs) > 0 {
+			msg := m.msgs[0]
+			m.msgs[0] = nil
+			m.msgs = m.msgs[1:]
+			m.mu.Unlock()
+			return msg, nil
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-m.notify:
+		case hc := <-health:
+			hc.answer()
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-own.Done():
+			return nil, context.Cause(own)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errWoops = errors.New("woops!")
+
+func receive(t *testing.T, p *Process) interface{} {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	msg, err := p.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// idle is a process function that runs until its context is done.
+func idle(p *Process) error {
+	<-p.Context().Done()
+	return nil
+}
+
+func crash(p *Process) error {
+	return errWoops
+}
+
+func TestLinkedProcessesExitTogether(t *testing.T) {
+	a := Spawn(context.Background(), idle)
+	SpawnLink(a, crash)
+
+	if err := a.Wait(); err != errWoops {
+		t.Fatalf("got %v, want %v", err, errWoops)
+	}
+}
+
+func TestNormalExitsDontTakeLinksDown(t *testing.T) {
+	a := Spawn(context.Background(), idle)
+	defer a.Kill()
+	b := SpawnLink(a, func(*Process) error { return nil })
+
+	if err := b.Wait(); err != Normal {
+		t.Fatalf("got %v, want %v", err, Normal)
+	}
+	if !a.Alive() {
+		t.Fatal("want the linked process alive")
+	}
+}
+
+func TestTrappingProcessesReceiveExits(t *testing.T) {
+	a := Spawn(context.Background(), idle)
+	defer a.Kill()
+	a.TrapExit(true)
+	b := SpawnLink(a, crash)
+
+	msg := receive(t, a)
+
+	if e, ok := msg.(Exit); !ok || e.PID != b.PID() || e.Reason != errWoops {
+		t.Fatalf("got %#v, want an Exit from %v", msg, b.PID())
+	}
+	if !a.Alive() {
+		t.Fatal("want the trapping process alive")
+	}
+}
+
+// parent is a process function that exits for reason once told to.
+func parent(exit <-chan error) func(*Process) error {
+	return func(*Process) error {
+		return <-exit
+	}
+}
+
+func TestNormalExitsDontTakeLinkedChildrenDown(t *testing.T) {
+	exit := make(chan error)
+	a := Spawn(context.Background(), parent(exit))
+	b := SpawnLink(a, idle)
+	defer b.Kill()
+
+	exit <- nil
+	a.Wait()
+
+	time.Sleep(10 * time.Millisecond)
+	if !b.Alive() {
+		t.Fatalf("got %v, want the linked child alive", b.Wait())
+	}
+}
+
+func TestLinkedChildrenExitWithTheirParent(t *testing.T) {
+	exit := make(chan error)
+	a := Spawn(context.Background(), parent(exit))
+	b := SpawnLink(a, idle)
+
+	exit <- errWoops
+
+	if err := b.Wait(); err != errWoops {
+		t.Fatalf("got %v, want %v", err, errWoops)
+	}
+}
+
+func TestTrappingChildrenReceiveTheirParentsExit(t *testing.T) {
+	exit := make(chan error)
+	a := Spawn(context.Background(), parent(exit))
+	got := make(chan interface{}, 1)
+	trapping := make(chan struct{})
+	b := SpawnLink(a, func(p *Process) error {
+		p.TrapExit(true)
+		close(trapping)
+		msg, err := p.Receive(context.Background())
+		if err != nil {
+			return err
+		}
+		got <- msg
+		<-p.Context().Done()
+		return nil
+	})
+	defer b.Kill()
+
+	<-trapping
+	exit <- errWoops
+
+	select {
+	case msg := <-got:
+		if e, ok := msg.(Exit); !ok || e.PID != a.PID() || e.Reason != errWoops {
+			t.Fatalf("got %#v, want an Exit from %v", msg, a.PID())
+		}
+	case <-b.Done():
+		t.Fatalf("got exit %v, want the child to trap it", b.Wait())
+	case <-time.After(2 * time.Second):
+		t.Fatal("want an Exit message")
+	}
+	if !b.Alive() {
+		t.Fatal("want the trapping child alive")
+	}
+}
+
+func TestKillCantBeTrapped(t *testing.T) {
+	a := Spawn(context.Background(), idle)
+	a.TrapExit(true)
+
+	a.Kill()
+
+	if err := a.Wait(); err != Killed {
+		t.Fatalf("got %v, want %v", err, Killed)
+	}
+}
+
+func TestMonitorsReceiveDown(t *testing.T) {
+	a := Spawn(context.Background(), idle)
+	defer a.Kill()
+	b := Spawn(context.Background(), idle)
+	ref := a.Monitor(b)
+
+	b.Kill()
+
+	msg := receive(t, a)
+	if d, ok := msg.(Down); !ok || d.Ref != ref || d.PID != b.PID() || d.Reason != Killed {
+		t.Fatalf("got %#v, want a Down for %v", msg, b.PID())
+	}
+	if !a.Alive() {
+		t.Fatal("want the monitoring process alive")
+	}
+}
+
+func TestLinkingToAnExitedProcessSignalsNoProc(t *testing.T) {
+	a := Spawn(context.Background(), idle)
+	defer a.Kill()
+	a.TrapExit(true)
+	b := Spawn(context.Background(), crash)
+	b.Wait()
+
+	a.Link(b)
+
+	if e, ok := receive(t, a).(Exit); !ok || e.Reason != NoProc {
+		t.Fatalf("got %#v, want an Exit with NoProc", e)
+	}
+}
+
+func TestPanicsBecomeExitReasons(t *testing.T) {
+	p := Spawn(context.Background(), func(*Process) error { panic("woops!") })
+
+	var e *PanicError
+	if err := p.Wait(); !errors.As(err, &e) || e.Value != "woops!" {
+		t.Fatalf("got %v, want a PanicError", err)
+	}
+}
+
+func TestSelfFindsTheProcess(t *testing.T) {
+	found := make(chan bool, 1)
+	p := Spawn(context.Background(), func(p *Process) error {
+		self, ok := Self(p.Context())
+		found <- ok && self == p
+		return nil
+	})
+	p.Wait()
+
+	if !<-found {
+		t.Fatal("want Self to return the process")
+	}
+}
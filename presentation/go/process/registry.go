@@ -0,0 +1,136 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registry errors.
+var (
+	ErrRegistered    = errors.New("name already registered")
+	ErrNotRegistered = errors.New("name not registered")
+	ErrNoReply       = errors.New("no reply")
+)
+
+var registry = struct {
+	sync.Mutex
+	names map[string]*Process
+}{names: map[string]*Process{}}
+
+// Register gives p a name, like erlang:register/2. The name is released
+// when p exits, so a restarted process can take it again.
+func Register(name string, p *Process) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if other, ok := registry.names[name]; ok && other.Alive() {
+		return fmt.Errorf("process: register %s: %w", name, ErrRegistered)
+	}
+	if !p.Alive() {
+		return fmt.Errorf("process: register %s: %w", name, NoProc)
+	}
+	registry.names[name] = p
+	return nil
+}
+
+// Unregister releases name if it is registered to p.
+func Unregister(name string, p *Process) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if registry.names[name] == p {
+		delete(registry.names, name)
+	}
+}
+
+// unregisterAll releases every name registered to p.
+func unregisterAll(p *Process) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for name, other := range registry.names {
+		if other == p {
+			delete(registry.names, name)
+		}
+	}
+}
+
+// Whereis returns the live process registered under name.
+func Whereis(name string) (*Process, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	p, ok := registry.names[name]
+	if !ok || !p.Alive() {
+		return nil, false
+	}
+	return p, true
+}
+
+// Registered returns the registered names, sorted.
+func Registered() []string {
+	registry.Lock()
+	defer registry.Unlock()
+
+	names := make([]string, 0, len(registry.names))
+	for name := range registry.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// healthCheck is the message Ping sends. It is handed straight to a
+// receiving process rather than queued in its mailbox, so a check nobody
+// takes is never left behind.
+type healthCheck struct {
+	reply chan struct{}
+}
+
+func (hc healthCheck) answer() {
+	hc.reply <- struct{}{}
+}
+
+// Ping asks the process registered under name whether it is responsive,
+// like sending health_check to worker.erl. A process answers from
+// Receive, or from the Mailbox an Actor runs on, so Ping fails with
+// ErrNoReply if the process is stuck, or never receives, for timeout. Use
+// Alive for workers that never receive.
+func Ping(name string, timeout time.Duration) error {
+	p, ok := Whereis(name)
+	if !ok {
+		return fmt.Errorf("process: ping %s: %w", name, ErrNotRegistered)
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	reply := make(chan struct{}, 1)
+	select {
+	case p.health <- healthCheck{reply: reply}:
+	case <-p.Done():
+		return fmt.Errorf("process: ping %s: exited: %w", name, p.Wait())
+	case <-t.C:
+		return fmt.Errorf("process: ping %s: %w after %v", name, ErrNoReply, timeout)
+	}
+
+	select {
+	case <-reply:
+		return nil
+	case <-p.Done():
+		return fmt.Errorf("process: ping %s: exited: %w", name, p.Wait())
+	case <-t.C:
+		return fmt.Errorf("process: ping %s: %w after %v", name, ErrNoReply, timeout)
+	}
+}
+
+// Alive reports whether a live process is registered under name. Unlike
+// Ping it needs no answer, so it says nothing about whether the process is
+// making progress.
+func Alive(name string) bool {
+	_, ok := Whereis(name)
+	return ok
+}
@@ -0,0 +1,84 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// receiver is a process function that reads its mailbox until done.
+func receiver(p *Process) error {
+	for {
+		if _, err := p.Receive(context.Background()); err != nil {
+			return nil
+		}
+	}
+}
+
+func TestRegisteredNamesAreReleasedOnExit(t *testing.T) {
+	p := Spawn(context.Background(), idle)
+	if err := Register("fetcher", p); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := Whereis("fetcher"); found != p {
+		t.Fatalf("got %v, want %v", found, p)
+	}
+	other := Spawn(context.Background(), idle)
+	defer other.Kill()
+	if err := Register("fetcher", other); !errors.Is(err, ErrRegistered) {
+		t.Fatalf("got %v, want %v", err, ErrRegistered)
+	}
+
+	p.Kill()
+	p.Wait()
+
+	if _, ok := Whereis("fetcher"); ok {
+		t.Fatal("want the name released")
+	}
+	q := Spawn(context.Background(), idle)
+	defer q.Kill()
+	if err := Register("fetcher", q); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPingAnswersWhileReceiving(t *testing.T) {
+	p := Spawn(context.Background(), receiver)
+	defer p.Kill()
+	Register("pinged", p)
+
+	if err := Ping("pinged", time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPingTimesOutOnStuckProcesses(t *testing.T) {
+	p := Spawn(context.Background(), idle)
+	defer p.Kill()
+	Register("stuck", p)
+
+	if err := Ping("stuck", 10*time.Millisecond); !errors.Is(err, ErrNoReply) {
+		t.Fatalf("got %v, want %v", err, ErrNoReply)
+	}
+	if !Alive("stuck") {
+		t.Fatal("want a stuck process reported alive")
+	}
+}
+
+func TestPingReportsUnregisteredNames(t *testing.T) {
+	if err := Ping("nobody", time.Second); !errors.Is(err, ErrNotRegistered) {
+		t.Fatalf("got %v, want %v", err, ErrNotRegistered)
+	}
+}
+
+func TestAliveReportsExitedProcesses(t *testing.T) {
+	p := Spawn(context.Background(), idle)
+	Register("exiting", p)
+	p.Kill()
+	p.Wait()
+
+	if Alive("exiting") {
+		t.Fatal("want an exited process reported dead")
+	}
+}
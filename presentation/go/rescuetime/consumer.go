@@ -0,0 +1,32 @@
+package rescuetime
+
+import "github.com/bwvoss/failure-patterns-essay/presentation/go/boundary"
+
+// Consumer runs a Fetch's Flow and hands failures to its error handler.
+type Consumer struct {
+	flow    *boundary.Flow[string, []Row]
+	handler *boundary.Handlers
+}
+
+// NewConsumer returns a Consumer for c.
+func NewConsumer(c Config) (*Consumer, error) {
+	flow := New(c).Flow()
+	handler, err := NewErrorHandler(flow.Steps(), c.MaxRejectRatio)
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{flow: flow, handler: handler}, nil
+}
+
+// Get returns the rows for the day of datetime. Errors are *boundary.Error
+// values keyed for the user, except in PartialRows mode when the rejected
+// rows are within MaxRejectRatio: then Get returns the rows it could parse
+// along with a *RejectedRows listing the others.
+func (c *Consumer) Get(datetime string) ([]Row, error) {
+	out := c.flow.Run(datetime)
+	rows, err := out.OnError(c.handler)
+	if err != nil {
+		return rows, err
+	}
+	return rows, out.Partial()
+}
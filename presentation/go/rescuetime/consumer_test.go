@@ -0,0 +1,135 @@
+package rescuetime
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/boundary"
+)
+
+func api(t *testing.T, body string) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func consumer(t *testing.T, s *httptest.Server) *Consumer {
+	c, err := NewConsumer(Config{APIURL: s.URL, APIKey: "8sdnjf7sdnf0", Timezone: "America/Chicago"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func i18n(err error) string {
+	var e *boundary.Error
+	if !errors.As(err, &e) {
+		return ""
+	}
+	return e.UserInfo()
+}
+
+func TestReturnsI18nForInvalidDates(t *testing.T) {
+	_, err := consumer(t, api(t, `{}`)).Get("not-a-date")
+
+	if i18n(err) != InvalidDate {
+		t.Fatalf("got %v, want %s", err, InvalidDate)
+	}
+}
+
+func TestReturnsI18nForInvalidAPIKeys(t *testing.T) {
+	_, err := consumer(t, api(t, `{"error":"# key not found","messages":"key not found"}`)).Get("2015-10-10")
+
+	if i18n(err) != InvalidAPIKey {
+		t.Fatalf("got %v, want %s", err, InvalidAPIKey)
+	}
+}
+
+func TestReturnsDefaultIfNoRows(t *testing.T) {
+	_, err := consumer(t, api(t, `{}`)).Get("2015-10-10")
+
+	if i18n(err) != boundary.DefaultI18n || !errors.Is(err, ErrNoRows) {
+		t.Fatalf("got %v, want %s", err, boundary.DefaultI18n)
+	}
+}
+
+func TestReturnsRows(t *testing.T) {
+	rows, err := consumer(t, api(t, `{"rows":[["2015-10-10T09:05:00",120,1,"Slack","Communication",1]]}`)).Get("2015-10-10")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Row{
+		Date:         time.Date(2015, 10, 10, 14, 5, 0, 0, time.UTC),
+		Seconds:      120,
+		People:       1,
+		Activity:     "Slack",
+		Category:     "Communication",
+		Productivity: 1,
+	}
+	if len(rows) != 1 || rows[0] != want {
+		t.Fatalf("got %+v, want %+v", rows, want)
+	}
+}
+
+func TestBuildsTheRequestURL(t *testing.T) {
+	u, _ := New(Config{APIURL: "http://someapi.com", APIKey: "8sdnjf7sdnf0"}).BuildURL("2015-10-10")
+
+	want := "http://someapi.com?format=json&key=8sdnjf7sdnf0&perspective=interval" +
+		"&resolution_time=minute&restrict_begin=2015-10-10&restrict_end=2015-10-10"
+	if u != want {
+		t.Fatalf("got %s, want %s", u, want)
+	}
+}
+
+func partialConsumer(t *testing.T, s *httptest.Server, maxRejectRatio float64) *Consumer {
+	c, err := NewConsumer(Config{
+		APIURL:         s.URL,
+		APIKey:         "8sdnjf7sdnf0",
+		Timezone:       "UTC",
+		PartialRows:    true,
+		MaxRejectRatio: maxRejectRatio,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+const oneBadRow = `{"rows":[
+	["2015-10-10T09:05:00",120,1,"Slack","Communication",1],
+	["2015-10-10T09:06:00",120,1,"Slack","Communication",1],
+	["2015-10-10T09:07:00","bad",1,"Slack","Communication",1]
+]}`
+
+func TestReturnsParsedRowsWhenFewAreRejected(t *testing.T) {
+	rows, err := partialConsumer(t, api(t, oneBadRow), 0.5).Get("2015-10-10")
+
+	var rejected *RejectedRows
+	if !errors.As(err, &rejected) || len(rows) != 2 {
+		t.Fatalf("got %d rows, %v; want 2 rows and the rejects", len(rows), err)
+	}
+	if len(rejected.Errors) != 1 || rejected.Errors[0].Index != 2 || rejected.Errors[0].Field != "seconds" {
+		t.Fatalf("got %+v, want row 2 rejected for its seconds", rejected)
+	}
+	if i18n(err) != "" {
+		t.Fatalf("got %v, want the rejects, not a failure for the user", err)
+	}
+}
+
+func TestReturnsInvalidRowsWhenTooManyAreRejected(t *testing.T) {
+	rows, err := partialConsumer(t, api(t, oneBadRow), 0.1).Get("2015-10-10")
+
+	var rejected *RejectedRows
+	if i18n(err) != InvalidRows || !errors.As(err, &rejected) || rows != nil {
+		t.Fatalf("got %v, %v; want %s", rows, err, InvalidRows)
+	}
+	if len(rejected.Errors) != 1 || rejected.Errors[0].Index != 2 || rejected.Total != 3 {
+		t.Fatalf("got %+v", rejected)
+	}
+}
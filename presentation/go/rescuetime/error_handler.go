@@ -0,0 +1,59 @@
+package rescuetime
+
+import (
+	"errors"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/boundary"
+)
+
+// The i18n keys a Consumer's errors carry.
+const (
+	InvalidDate   = "invalid_date"
+	InvalidAPIKey = "invalid_api_key"
+	InvalidRows   = "invalid_rows"
+)
+
+// keyNotFound is the API's error for an unknown key.
+const keyNotFound = "# key not found"
+
+// NewErrorHandler returns the handlers for a Fetch's Flow. A partial
+// parse_rows failure is accepted when no more than maxRejectRatio of the rows
+// were rejected.
+func NewErrorHandler(steps []string, maxRejectRatio float64) (*boundary.Handlers, error) {
+	return boundary.NewHandlers(steps, defaultHandler, map[string]boundary.StepHandler{
+		"format_date": formatDate,
+		"fetch_rows":  fetchRows,
+		"parse_rows":  parseRows(maxRejectRatio),
+	})
+}
+
+func formatDate(data interface{}, err error) error {
+	return boundary.NewError(err, InvalidDate)
+}
+
+func fetchRows(data interface{}, err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Message == keyNotFound {
+		return boundary.NewError(err, InvalidAPIKey)
+	}
+	return defaultHandler(data, err)
+}
+
+func parseRows(maxRejectRatio float64) boundary.StepHandler {
+	return func(data interface{}, err error) error {
+		var rejected *RejectedRows
+		if errors.As(err, &rejected) && rejected.Ratio() <= maxRejectRatio {
+			boundary.Logger.Println(err)
+			return nil
+		}
+		var rowErrs RowErrors
+		if errors.As(err, &rowErrs) {
+			return boundary.NewError(err, InvalidRows)
+		}
+		return defaultHandler(data, err)
+	}
+}
+
+func defaultHandler(data interface{}, err error) error {
+	return boundary.NewError(err, boundary.DefaultI18n)
+}
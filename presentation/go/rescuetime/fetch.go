@@ -0,0 +1,145 @@
+// Package rescuetime fetches a day of activity from the Rescuetime API as a
+// boundary.Flow, one step per stage: format_date, build_url, request,
+// fetch_rows and parse_rows.
+package rescuetime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/boundary"
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/fetch"
+)
+
+// Config configures a Fetch.
+type Config struct {
+	APIURL   string
+	APIKey   string
+	Timezone string
+	Fetch    fetch.Config
+
+	// PartialRows makes parse_rows keep the rows it could parse and fail
+	// with a partial *RejectedRows instead of rejecting the whole batch.
+	PartialRows bool
+
+	// MaxRejectRatio is the share of rejected rows the error handler
+	// accepts in PartialRows mode.
+	MaxRejectRatio float64
+}
+
+// Row is one interval of activity.
+type Row struct {
+	Date         time.Time `json:"date"`
+	Seconds      int       `json:"time_spent_in_seconds"`
+	People       int       `json:"number_of_people"`
+	Activity     string    `json:"activity"`
+	Category     string    `json:"category"`
+	Productivity int       `json:"productivity"`
+}
+
+// RawRow is a row as the API sends it: date, seconds, people, activity,
+// category and productivity.
+type RawRow []json.RawMessage
+
+// ErrNoRows is returned by FetchRows for a response without rows.
+var ErrNoRows = errors.New("rescuetime: fetch_rows: response has no rows")
+
+// APIError is returned by FetchRows for a response reporting an error.
+type APIError struct {
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return "rescuetime: api error: " + e.Message
+}
+
+// Fetch holds the steps of a Rescuetime request.
+type Fetch struct {
+	config Config
+	client *fetch.Client
+}
+
+// New returns a Fetch for c.
+func New(c Config) *Fetch {
+	return &Fetch{config: c, client: fetch.New(c.Fetch)}
+}
+
+// Flow returns the steps chained in order.
+func (f *Fetch) Flow() *boundary.Flow[string, []Row] {
+	date := boundary.Start("format_date", FormatDate)
+	u := boundary.Then(date, "build_url", f.BuildURL)
+	body := boundary.Then(u, "request", f.Request)
+	rows := boundary.Then(body, "fetch_rows", FetchRows)
+	return boundary.Then(rows, "parse_rows", f.ParseRows)
+}
+
+// dateLayouts are the forms of datetime FormatDate accepts.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// FormatDate returns the date of datetime as YYYY-MM-DD.
+func FormatDate(datetime string) (string, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, datetime); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("rescuetime: format_date: %q is not a date", datetime)
+}
+
+// BuildURL returns the API URL for a day of minute-by-minute intervals.
+func (f *Fetch) BuildURL(date string) (string, error) {
+	if f.config.APIURL == "" || f.config.APIKey == "" {
+		return "", errors.New("rescuetime: build_url: api url and key are required")
+	}
+	u, err := url.Parse(f.config.APIURL)
+	if err != nil {
+		return "", fmt.Errorf("rescuetime: build_url: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("key", f.config.APIKey)
+	q.Set("restrict_begin", date)
+	q.Set("restrict_end", date)
+	q.Set("perspective", "interval")
+	q.Set("resolution_time", "minute")
+	q.Set("format", "json")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Request fetches u and returns the response body.
+func (f *Fetch) Request(u string) ([]byte, error) {
+	body, err := f.client.Get(context.Background(), u)
+	if err != nil {
+		return nil, fmt.Errorf("rescuetime: %w", err)
+	}
+	return body, nil
+}
+
+// FetchRows pulls the rows out of a response body.
+func FetchRows(body []byte) ([]RawRow, error) {
+	var resp struct {
+		Rows  *[]RawRow `json:"rows"`
+		Error string    `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("rescuetime: fetch_rows: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, &APIError{Message: resp.Error}
+	}
+	if resp.Rows == nil {
+		return nil, ErrNoRows
+	}
+	return *resp.Rows, nil
+}
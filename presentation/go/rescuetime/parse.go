@@ -0,0 +1,176 @@
+package rescuetime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/boundary"
+)
+
+// dateLayout is how the API writes a row's date, in the account's timezone.
+const dateLayout = "2006-01-02T15:04:05"
+
+// columns are the fields of a RawRow, in order, with the JSON type of each.
+var columns = []struct{ name, kind string }{
+	{"date", "string"},
+	{"seconds", "number"},
+	{"people", "number"},
+	{"activity", "string"},
+	{"category", "string"},
+	{"productivity", "number"},
+}
+
+// ErrNoTimezone is returned by ParseRows when no timezone is configured.
+var ErrNoTimezone = errors.New("rescuetime: parse_rows: no timezone configured")
+
+// ZoneError is returned by ParseRows when the configured timezone isn't in
+// the tz database.
+type ZoneError struct {
+	Name string
+	Err  error
+}
+
+func (e *ZoneError) Error() string {
+	return fmt.Sprintf("rescuetime: parse_rows: unknown timezone %q: %v", e.Name, e.Err)
+}
+
+func (e *ZoneError) Unwrap() error {
+	return e.Err
+}
+
+// RowError describes a row that couldn't be parsed.
+type RowError struct {
+	Index  int
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("row %d: %s: %s", e.Index, e.Value, e.Reason)
+	}
+	return fmt.Sprintf("row %d: %s %s: %s", e.Index, e.Field, e.Value, e.Reason)
+}
+
+// RowErrors lists every row ParseRows rejected.
+type RowErrors []*RowError
+
+func (e RowErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("rescuetime: parse_rows: %d bad rows: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// RejectedRows is the partial failure of ParseRows in PartialRows mode: the
+// rows it rejected, out of Total.
+type RejectedRows struct {
+	Total  int
+	Errors RowErrors
+}
+
+// Ratio returns the share of rows that were rejected.
+func (e *RejectedRows) Ratio() float64 {
+	if e.Total == 0 {
+		return 0
+	}
+	return float64(len(e.Errors)) / float64(e.Total)
+}
+
+func (e *RejectedRows) Error() string {
+	return fmt.Sprintf("rescuetime: parse_rows: rejected %d of %d rows: %v", len(e.Errors), e.Total, e.Errors)
+}
+
+func (e *RejectedRows) Unwrap() error {
+	return e.Errors
+}
+
+// ParseRows maps raw rows into Rows, reading each date in the configured
+// timezone and converting it to UTC. Every bad row is reported in a
+// RowErrors, or, in PartialRows mode, in a *RejectedRows wrapped by
+// boundary.Partial along with the rows that parsed.
+func (f *Fetch) ParseRows(raw []RawRow) ([]Row, error) {
+	if f.config.Timezone == "" {
+		return nil, ErrNoTimezone
+	}
+	loc, err := time.LoadLocation(f.config.Timezone)
+	if err != nil {
+		return nil, &ZoneError{Name: f.config.Timezone, Err: err}
+	}
+
+	rows := make([]Row, 0, len(raw))
+	var errs RowErrors
+	for i, r := range raw {
+		row, err := parseRow(i, r, loc)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(errs) == 0 {
+		return rows, nil
+	}
+	if f.config.PartialRows {
+		return rows, boundary.Partial(rows, &RejectedRows{Total: len(raw), Errors: errs})
+	}
+	return nil, errs
+}
+
+func parseRow(index int, r RawRow, loc *time.Location) (Row, *RowError) {
+	var row Row
+	if len(r) != len(columns) {
+		value, _ := json.Marshal(r)
+		return row, &RowError{Index: index, Value: string(value), Reason: fmt.Sprintf("got %d fields, want %d", len(r), len(columns))}
+	}
+
+	var date string
+	fields := []interface{}{&date, &row.Seconds, &row.People, &row.Activity, &row.Category, &row.Productivity}
+	for i, f := range fields {
+		if err := json.Unmarshal(r[i], f); err != nil {
+			c := columns[i]
+			return row, &RowError{Index: index, Field: c.name, Value: string(r[i]), Reason: "want a " + c.kind}
+		}
+	}
+
+	wall, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return row, &RowError{Index: index, Field: "date", Value: string(r[0]), Reason: "want " + dateLayout}
+	}
+	row.Date = inZone(wall, loc)
+	return row, nil
+}
+
+// inZone reads the wall clock of wall, a UTC time, in loc and returns the
+// instant in UTC. A wall clock skipped by a DST gap is moved forward by the
+// length of the gap, and one repeated by a DST overlap is read as its first
+// occurrence.
+func inZone(wall time.Time, loc *time.Location) time.Time {
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var first time.Time
+	for _, offset := range []int{before, after} {
+		t := wall.Add(-time.Duration(offset) * time.Second)
+		if sameWall(t.In(loc), wall) && (first.IsZero() || t.Before(first)) {
+			first = t
+		}
+	}
+	if first.IsZero() {
+		return wall.Add(-time.Duration(before) * time.Second)
+	}
+	return first
+}
+
+func sameWall(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	ah, amin, as := a.Clock()
+	bh, bmin, bs := b.Clock()
+	return ay == by && am == bm && ad == bd && ah == bh && amin == bmin && as == bs
+}
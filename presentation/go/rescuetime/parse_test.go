@@ -0,0 +1,77 @@
+package rescuetime
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func rawRows(t *testing.T, s string) []RawRow {
+	var rows []RawRow
+	if err := json.Unmarshal([]byte(s), &rows); err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func parse(t *testing.T, timezone, rows string) ([]Row, error) {
+	return New(Config{Timezone: timezone}).ParseRows(rawRows(t, rows))
+}
+
+func TestParseRowsConvertsToUTC(t *testing.T) {
+	tests := []struct {
+		name, date string
+		want       time.Time
+	}{
+		{"standard time", "2015-01-10T09:05:00", time.Date(2015, 1, 10, 15, 5, 0, 0, time.UTC)},
+		{"daylight time", "2015-07-10T09:05:00", time.Date(2015, 7, 10, 14, 5, 0, 0, time.UTC)},
+		{"dst gap moves forward", "2015-03-08T02:30:00", time.Date(2015, 3, 8, 8, 30, 0, 0, time.UTC)},
+		{"dst overlap reads the first", "2015-11-01T01:30:00", time.Date(2015, 11, 1, 6, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		rows, err := parse(t, "America/Chicago", `[["`+tt.date+`",60,1,"Slack","Communication",1]]`)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !rows[0].Date.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, rows[0].Date, tt.want)
+		}
+	}
+}
+
+func TestParseRowsRejectsUnknownTimezones(t *testing.T) {
+	_, err := parse(t, "America/Nowhere", `[]`)
+
+	var e *ZoneError
+	if !errors.As(err, &e) || e.Name != "America/Nowhere" {
+		t.Fatalf("got %v, want a ZoneError", err)
+	}
+	if _, err := parse(t, "", `[]`); err != ErrNoTimezone {
+		t.Fatalf("got %v, want %v", err, ErrNoTimezone)
+	}
+}
+
+func TestParseRowsReportsEveryBadRow(t *testing.T) {
+	_, err := parse(t, "UTC", `[
+		["2015-10-10T09:05:00",60,1,"Slack","Communication",1],
+		["yesterday",60,1,"Slack","Communication",1],
+		["2015-10-10T09:06:00","sixty",1,"Slack","Communication",1],
+		["2015-10-10T09:07:00",60]
+	]`)
+
+	var errs RowErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("got %v, want 3 RowErrors", err)
+	}
+	want := []RowError{
+		{Index: 1, Field: "date", Value: `"yesterday"`, Reason: "want " + dateLayout},
+		{Index: 2, Field: "seconds", Value: `"sixty"`, Reason: "want a number"},
+		{Index: 3, Value: `["2015-10-10T09:07:00",60]`, Reason: "got 2 fields, want 6"},
+	}
+	for i, e := range errs {
+		if *e != want[i] {
+			t.Errorf("got %+v, want %+v", *e, want[i])
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package stream
+
+// Map returns an Observable of fn applied to each value of o. An error or
+// panic from fn ends the stream.
+func Map[T, U any](o *Observable[T], fn func(T) (U, error)) *Observable[U] {
+	return Create(func(down *Subscriber[U]) {
+		operate(o, down, func(v T) {
+			u, err := try(fn, v)
+			if err != nil {
+				down.OnError(err)
+				return
+			}
+			down.OnNext(u)
+		})
+	})
+}
+
+// Filter returns an Observable of the values of o that keep accepts. An
+// error or panic from keep ends the stream.
+func Filter[T any](o *Observable[T], keep func(T) (bool, error)) *Observable[T] {
+	return Create(func(down *Subscriber[T]) {
+		operate(o, down, func(v T) {
+			ok, err := try(keep, v)
+			if err != nil {
+				down.OnError(err)
+				return
+			}
+			if ok {
+				down.OnNext(v)
+			}
+		})
+	})
+}
@@ -0,0 +1,95 @@
+package stream
+
+import "time"
+
+// MaxRetryDelay caps the wait between Retry's attempts.
+const MaxRetryDelay = 30 * time.Second
+
+// closedPoll is how often Retry checks, while it waits, whether the
+// stream has been closed.
+const closedPoll = 10 * time.Millisecond
+
+// Retry returns an Observable that resubscribes to o when it fails, up to
+// n times, waiting delay before the first retry and twice as long before
+// each after, up to MaxRetryDelay. Values emitted before a failure are not
+// taken back. The failures retried are passed to OnRecovered; the last
+// one, if o still fails, ends the stream.
+//
+// The wait and the resubscription happen inside the failed subscription's
+// OnError, which holds that subscriber's lock, so each retry runs nested
+// in the one before. The wait ends early if the stream is closed.
+func Retry[T any](o *Observable[T], n int, delay time.Duration) *Observable[T] {
+	return Create(func(down *Subscriber[T]) {
+		var attempt func(i int)
+		attempt = func(i int) {
+			relay(o, down, func(err error) {
+				if i >= n || down.Closed() {
+					down.OnError(err)
+					return
+				}
+				down.OnRecovered(err)
+				if wait(down, retryDelay(delay, i)) {
+					attempt(i + 1)
+				}
+			})
+		}
+		attempt(0)
+	})
+}
+
+// retryDelay returns delay doubled i times, capped at MaxRetryDelay.
+func retryDelay(delay time.Duration, i int) time.Duration {
+	for ; i > 0 && delay < MaxRetryDelay; i-- {
+		delay *= 2
+	}
+	if delay > MaxRetryDelay {
+		return MaxRetryDelay
+	}
+	return delay
+}
+
+// wait waits d, checking every closedPoll whether s is closed. It returns
+// false, as soon as it notices, if s is.
+func wait(s interface{ Closed() bool }, d time.Duration) bool {
+	deadline := time.Now().Add(d)
+	for !s.Closed() {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return true
+		}
+		time.Sleep(min(left, closedPoll))
+	}
+	return false
+}
+
+// Catch returns an Observable that switches to the Observable fallback
+// returns when o fails, passing the failure to OnRecovered. If fallback
+// returns nil, the failure ends the stream.
+func Catch[T any](o *Observable[T], fallback func(error) *Observable[T]) *Observable[T] {
+	return Create(func(down *Subscriber[T]) {
+		relay(o, down, func(err error) {
+			next, perr := try(func(err error) (*Observable[T], error) { return fallback(err), nil }, err)
+			switch {
+			case perr != nil:
+				down.OnError(perr)
+			case next == nil:
+				down.OnError(err)
+			default:
+				down.OnRecovered(err)
+				relay(next, down, down.OnError)
+			}
+		})
+	})
+}
+
+// OnErrorResumeNext returns an Observable that switches to next when o
+// fails, passing the failure to OnRecovered.
+func OnErrorResumeNext[T any](o, next *Observable[T]) *Observable[T] {
+	return Catch(o, func(error) *Observable[T] { return next })
+}
+
+// OnErrorReturn returns an Observable that emits value and completes when
+// o fails, passing the failure to OnRecovered.
+func OnErrorReturn[T any](o *Observable[T], value T) *Observable[T] {
+	return Catch(o, func(error) *Observable[T] { return Of(value) })
+}
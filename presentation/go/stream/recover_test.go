@@ -0,0 +1,150 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// recovering is a recorder that also sees swallowed errors.
+type recovering struct {
+	recorder
+}
+
+func (r *recovering) observer() Observer[int] {
+	return r
+}
+
+func (r *recovering) OnNext(x int)        { r.add("onNext: %d", x) }
+func (r *recovering) OnError(e error)     { r.add("onError: %v", e) }
+func (r *recovering) OnCompleted()        { r.add("onCompleted") }
+func (r *recovering) OnRecovered(e error) { r.add("onRecovered: %v", e) }
+
+// flaky returns a cold source that emits 1 then fails on its first
+// failures subscriptions, and emits 1, 2 and completes after that.
+func flaky(failures int) (*Observable[int], *int) {
+	subscriptions := 0
+	return Create(func(s *Subscriber[int]) {
+		subscriptions++
+		s.OnNext(1)
+		if subscriptions <= failures {
+			s.OnError(fmt.Errorf("woops %d", subscriptions))
+			return
+		}
+		s.OnNext(2)
+		s.OnCompleted()
+	}), &subscriptions
+}
+
+func TestRetryResubscribes(t *testing.T) {
+	r := &recovering{}
+	source, subscriptions := flaky(2)
+
+	begin := time.Now()
+	Retry(source, 3, 5*time.Millisecond).Subscribe(r.observer())
+
+	want := "onNext: 1, onRecovered: woops 1, onNext: 1, onRecovered: woops 2, onNext: 1, onNext: 2, onCompleted"
+	if r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+	if *subscriptions != 3 {
+		t.Fatalf("got %d subscriptions, want 3", *subscriptions)
+	}
+	if took := time.Since(begin); took < 15*time.Millisecond {
+		t.Fatalf("took %v, want at least 5ms and 10ms of backoff", took)
+	}
+}
+
+func TestRetryGivesUpAfterN(t *testing.T) {
+	r := &recovering{}
+	source, _ := flaky(5)
+
+	Retry(source, 1, 0).Subscribe(r.observer())
+
+	if want := "onNext: 1, onRecovered: woops 1, onNext: 1, onError: woops 2"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestCatchSwitchesToTheFallback(t *testing.T) {
+	r := &recovering{}
+	source, _ := flaky(1)
+
+	Catch(source, func(err error) *Observable[int] { return Of(7, 8) }).Subscribe(r.observer())
+
+	if want := "onNext: 1, onRecovered: woops 1, onNext: 7, onNext: 8, onCompleted"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestCatchRethrowsWithoutAFallback(t *testing.T) {
+	r := &recovering{}
+	source, _ := flaky(1)
+
+	Catch(source, func(err error) *Observable[int] { return nil }).Subscribe(r.observer())
+
+	if want := "onNext: 1, onError: woops 1"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+
+	r = &recovering{}
+	failing := Create(func(s *Subscriber[int]) { s.OnError(errors.New("woops")) })
+	Catch(failing, func(err error) *Observable[int] { panic("woops!") }).Subscribe(r.observer())
+	if want := "onError: panic: woops!"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestOnErrorReturnAndResumeNext(t *testing.T) {
+	r := &recovering{}
+	Map(OnErrorReturn(Create(func(s *Subscriber[int]) { s.OnError(errors.New("woops!")) }), 0), double).
+		Subscribe(r.observer())
+	OnErrorResumeNext(Create(func(s *Subscriber[int]) { panic("woops!") }), Of(3)).
+		Subscribe(r.observer())
+
+	if want := "onRecovered: woops!, onNext: 0, onCompleted, onRecovered: panic: woops!, onNext: 3, onCompleted"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestNewRecovererSeesRecoveredErrors(t *testing.T) {
+	r := &recorder{}
+	source, _ := flaky(1)
+	observer := NewRecoverer(
+		func(x int) { r.add("onNext: %d", x) },
+		func(e error) { r.add("onError: %v", e) },
+		func() { r.add("onCompleted") },
+		func(e error) { r.add("onRecovered: %v", e) },
+	)
+
+	Retry(source, 1, 0).Subscribe(observer)
+
+	if want := "onNext: 1, onRecovered: woops 1, onNext: 1, onNext: 2, onCompleted"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestRetryStopsWaitingOnceClosed(t *testing.T) {
+	r := &recovering{}
+	s := newSubscriber(r.observer(), nil)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		s.OnCompleted()
+	}()
+
+	start := time.Now()
+	if wait(s, MaxRetryDelay) {
+		t.Fatal("want the wait cut short")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("waited %v after the stream closed", elapsed)
+	}
+}
+
+func TestRetryDelaysAreCapped(t *testing.T) {
+	got := []time.Duration{retryDelay(time.Second, 0), retryDelay(time.Second, 3), retryDelay(time.Second, 200), retryDelay(time.Hour, 0)}
+	if fmt.Sprint(got) != "[1s 8s 30s 30s]" {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,215 @@
+// Package stream is a small take on the Rx observables of
+// presentation/rxjs/errors.js: a source emits values through operators to an
+// Observer with separate callbacks for values, the error and completion. An
+// error or panic anywhere ends the stream through OnError, once.
+package stream
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Observer receives a stream. OnNext is called for each value, then either
+// OnError or OnCompleted once, and nothing after that.
+type Observer[T any] interface {
+	OnNext(value T)
+	OnError(err error)
+	OnCompleted()
+}
+
+// Recoverer is an Observer that also wants to see the errors that recovery
+// operators such as Retry and Catch swallow. OnRecovered is called for each,
+// before the stream ends. NewRecoverer makes one from functions.
+type Recoverer interface {
+	OnRecovered(err error)
+}
+
+// NewObserver returns an Observer calling next, err and completed, like
+// Rx.Observer.create. Any of them may be nil.
+func NewObserver[T any](next func(T), err func(error), completed func()) Observer[T] {
+	return &funcs[T]{next: next, err: err, completed: completed}
+}
+
+// NewRecoverer is NewObserver, also calling recovered for each error a
+// recovery operator swallows. Any of the functions may be nil.
+func NewRecoverer[T any](next func(T), err func(error), completed func(), recovered func(error)) Observer[T] {
+	return &funcs[T]{next: next, err: err, completed: completed, recovered: recovered}
+}
+
+type funcs[T any] struct {
+	next      func(T)
+	err       func(error)
+	completed func()
+	recovered func(error)
+}
+
+func (f *funcs[T]) OnNext(value T) {
+	if f.next != nil {
+		f.next(value)
+	}
+}
+
+func (f *funcs[T]) OnError(err error) {
+	if f.err != nil {
+		f.err(err)
+	}
+}
+
+func (f *funcs[T]) OnCompleted() {
+	if f.completed != nil {
+		f.completed()
+	}
+}
+
+func (f *funcs[T]) OnRecovered(err error) {
+	if f.recovered != nil {
+		f.recovered(err)
+	}
+}
+
+// PanicError is the error of a stream whose source or operator panicked.
+type PanicError struct {
+	Value interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Subscriber is the Observer a source emits to. It keeps the Observer
+// contract whatever the source does, and tells the source when to stop.
+type Subscriber[T any] struct {
+	observer Observer[T]
+	parent   interface{ Closed() bool }
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newSubscriber[T any](o Observer[T], parent interface{ Closed() bool }) *Subscriber[T] {
+	return &Subscriber[T]{observer: o, parent: parent}
+}
+
+// OnNext passes value on unless the stream has ended.
+func (s *Subscriber[T]) OnNext(value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.observer.OnNext(value)
+	}
+}
+
+// OnError ends the stream with err unless it has already ended.
+func (s *Subscriber[T]) OnError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		s.observer.OnError(err)
+	}
+}
+
+// OnCompleted ends the stream unless it has already ended.
+func (s *Subscriber[T]) OnCompleted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		s.observer.OnCompleted()
+	}
+}
+
+// OnRecovered passes on an error a recovery operator swallowed, if the
+// Observer is a Recoverer and the stream hasn't ended.
+func (s *Subscriber[T]) OnRecovered(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.observer.(Recoverer); ok && !s.closed {
+		r.OnRecovered(err)
+	}
+}
+
+// Closed reports whether the stream has ended, or its subscriber
+// downstream has, so the source can stop emitting.
+func (s *Subscriber[T]) Closed() bool {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	return closed || (s.parent != nil && s.parent.Closed())
+}
+
+// Observable is a stream of T. It is cold: each Subscribe runs its source
+// again.
+type Observable[T any] struct {
+	source func(s *Subscriber[T])
+}
+
+// Create returns an Observable whose source emits to s. A source that
+// panics ends the stream with a *PanicError.
+func Create[T any](source func(s *Subscriber[T])) *Observable[T] {
+	return &Observable[T]{source: source}
+}
+
+// Of returns an Observable of values that then completes.
+func Of[T any](values ...T) *Observable[T] {
+	return Create(func(s *Subscriber[T]) {
+		for _, v := range values {
+			if s.Closed() {
+				return
+			}
+			s.OnNext(v)
+		}
+		s.OnCompleted()
+	})
+}
+
+// Subscribe runs the source, emitting to o. With a source like Of's, it
+// returns once the stream has ended.
+func (o *Observable[T]) Subscribe(observer Observer[T]) {
+	o.subscribe(newSubscriber(observer, nil))
+}
+
+func (o *Observable[T]) subscribe(s *Subscriber[T]) {
+	defer func() {
+		if v := recover(); v != nil {
+			s.OnError(&PanicError{Value: v})
+		}
+	}()
+	o.source(s)
+}
+
+// operate subscribes to o with next, passing the end of the stream on to
+// down. next isn't called once down has ended.
+func operate[T, U any](o *Observable[T], down *Subscriber[U], next func(T)) {
+	o.subscribe(newSubscriber[T](&funcs[T]{
+		next: func(v T) {
+			if !down.Closed() {
+				next(v)
+			}
+		},
+		err:       down.OnError,
+		completed: down.OnCompleted,
+		recovered: down.OnRecovered,
+	}, down))
+}
+
+// relay subscribes to o, passing everything on to down but an error,
+// which goes to onError.
+func relay[T any](o *Observable[T], down *Subscriber[T], onError func(error)) {
+	o.subscribe(newSubscriber[T](&funcs[T]{
+		next:      down.OnNext,
+		err:       onError,
+		completed: down.OnCompleted,
+		recovered: down.OnRecovered,
+	}, down))
+}
+
+// try runs an operator's fn, turning a panic into a *PanicError.
+func try[T, U any](fn func(T) (U, error), v T) (u U, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r}
+		}
+	}()
+	return fn(v)
+}
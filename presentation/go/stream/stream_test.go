@@ -0,0 +1,137 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recorder is an Observer that records what it's told.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) observer() Observer[int] {
+	return NewObserver(
+		func(x int) { r.add("onNext: %d", x) },
+		func(e error) { r.add("onError: %v", e) },
+		func() { r.add("onCompleted") },
+	)
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.events, ", ")
+}
+
+func double(x int) (int, error) { return x * 2, nil }
+
+func TestMapsAndFilters(t *testing.T) {
+	r := &recorder{}
+	big := func(x int) (bool, error) { return x > 4, nil }
+
+	Filter(Map(Of(1, 2, 3, 4, 5), double), big).Subscribe(r.observer())
+
+	if want := "onNext: 6, onNext: 8, onNext: 10, onCompleted"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestPanicsInOperatorsEndTheStream(t *testing.T) {
+	r := &recorder{}
+	woops := func(x int) (bool, error) {
+		if x == 4 {
+			panic("woops!")
+		}
+		return true, nil
+	}
+
+	Filter(Map(Of(1, 2, 3, 4, 5), double), woops).Subscribe(r.observer())
+
+	if want := "onNext: 2, onError: panic: woops!"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestErrorsFromOperatorsEndTheStream(t *testing.T) {
+	r := &recorder{}
+	calls := 0
+	woops := func(x int) (int, error) {
+		calls++
+		if x == 2 {
+			return 0, errors.New("woops!")
+		}
+		return x, nil
+	}
+
+	Map(Of(1, 2, 3), woops).Subscribe(r.observer())
+
+	if want := "onNext: 1, onError: woops!"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls, want the source stopped after the error", calls)
+	}
+}
+
+func TestSourcesCannotBreakTheContract(t *testing.T) {
+	r := &recorder{}
+	source := Create(func(s *Subscriber[int]) {
+		s.OnNext(1)
+		s.OnError(errors.New("first"))
+		s.OnNext(2)
+		s.OnError(errors.New("second"))
+		s.OnCompleted()
+		panic("third")
+	})
+
+	Map(source, double).Subscribe(r.observer())
+
+	if want := "onNext: 2, onError: first"; r.String() != want {
+		t.Fatalf("got %s, want %s", r, want)
+	}
+}
+
+func TestConcurrentSourcesEndOnce(t *testing.T) {
+	r := &recorder{}
+	source := Create(func(s *Subscriber[int]) {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.OnNext(i)
+				if i%10 == 0 {
+					s.OnError(fmt.Errorf("woops %d", i))
+				}
+			}(i)
+		}
+		wg.Wait()
+		s.OnCompleted()
+	})
+
+	Map(source, double).Subscribe(r.observer())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := r.events[len(r.events)-1]
+	ends := 0
+	for _, e := range r.events {
+		if strings.HasPrefix(e, "onError") || e == "onCompleted" {
+			ends++
+		}
+	}
+	if ends != 1 || !strings.HasPrefix(last, "onError: woops") {
+		t.Fatalf("got %v, want one error, last", r.events)
+	}
+}
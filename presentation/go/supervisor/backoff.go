@@ -0,0 +1,121 @@
+package supervisor
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Defaults used when a Backoff leaves a field unset.
+const (
+	DefaultBackoffMax   = 30 * time.Second
+	DefaultBackoffReset = time.Minute
+)
+
+// maxAttempts is how many restart attempts a child remembers.
+const maxAttempts = 100
+
+// Backoff delays the restarts of a child that keeps crashing, so an outage
+// of something it depends on doesn't become a hot crash loop. The nth
+// restart in a row waits a random time up to Base doubled n-1 times, capped
+// at Max. A child that stays up for Reset starts over from Base. The zero
+// Backoff restarts at once.
+type Backoff struct {
+	Base  time.Duration
+	Max   time.Duration
+	Reset time.Duration
+}
+
+// delay returns how long to wait before the nth restart in a row.
+func (b Backoff) delay(n int) time.Duration {
+	if b.Base <= 0 {
+		return 0
+	}
+	max := b.Max
+	if max <= 0 {
+		max = DefaultBackoffMax
+	}
+	d := b.Base
+	for i := 1; i < n && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return jitter(d)
+}
+
+func (b Backoff) reset() time.Duration {
+	if b.Reset <= 0 {
+		return DefaultBackoffReset
+	}
+	return b.Reset
+}
+
+// jitter returns a random duration from 0 to d, for full jitter.
+var jitter = func(d time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// Attempt records one restart of a child: why it exited, and how long the
+// supervisor waited before starting it again.
+type Attempt struct {
+	At     time.Time
+	Delay  time.Duration
+	Reason error
+}
+
+// Restarts returns the restart attempts of the child named name, oldest
+// first, up to the last 100.
+func (s *Supervisor) Restarts(name string) ([]Attempt, error) {
+	var attempts []Attempt
+	err := s.call(func(r *run) error {
+		for _, c := range r.children {
+			if c.spec.Name == name {
+				attempts = append(attempts, c.attempts...)
+				return nil
+			}
+		}
+		return ErrNoChild
+	})
+	return attempts, err
+}
+
+// backoff records a restart of c after it exited for reason and returns
+// how long to wait before it.
+func (c *child) backoff(reason error, now time.Time) time.Duration {
+	b := c.spec.Backoff
+	if now.Sub(c.startedAt) >= b.reset() {
+		c.failures = 0
+	}
+	c.failures++
+
+	d := b.delay(c.failures)
+	c.attempts = append(c.attempts, Attempt{At: now, Delay: d, Reason: reason})
+	if len(c.attempts) > maxAttempts {
+		c.attempts = c.attempts[len(c.attempts)-maxAttempts:]
+	}
+	return d
+}
+
+// delayed is a restart waiting out its backoff.
+type delayed struct {
+	child   *child
+	restart func()
+}
+
+// after runs restart in the Run loop once d has passed, unless c has been
+// removed, terminated or started again in the meantime.
+func (r *run) after(d time.Duration, c *child, restart func()) {
+	time.AfterFunc(d, func() {
+		select {
+		case r.delayed <- delayed{child: c, restart: restart}:
+		case <-r.ctx.Done():
+		}
+	})
+}
+
+func (r *run) restartDelayed(d delayed) {
+	if r.index(d.child) < len(r.children) && d.child.waiting {
+		d.restart()
+	}
+}
@@ -0,0 +1,90 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withoutJitter(t *testing.T) {
+	saved := jitter
+	jitter = func(d time.Duration) time.Duration { return d }
+	t.Cleanup(func() { jitter = saved })
+}
+
+func TestBackoffDoublesUpToItsCap(t *testing.T) {
+	withoutJitter(t)
+	b := Backoff{Base: time.Second, Max: 5 * time.Second}
+
+	var got []time.Duration
+	for n := 1; n <= 5; n++ {
+		got = append(got, b.delay(n))
+	}
+	if fmt.Sprint(got) != "[1s 2s 4s 5s 5s]" {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestBackoffJittersBelowTheDelay(t *testing.T) {
+	b := Backoff{Base: time.Second}
+	for i := 0; i < 100; i++ {
+		if d := b.delay(3); d < 0 || d > 4*time.Second {
+			t.Fatalf("got %v, want at most 4s", d)
+		}
+	}
+}
+
+func TestBackoffResetsAfterStableUptime(t *testing.T) {
+	withoutJitter(t)
+	c := &child{spec: ChildSpec{Backoff: Backoff{Base: time.Second, Reset: time.Minute}}}
+	now := time.Now()
+
+	c.startedAt = now
+	c.backoff(errors.New("woops!"), now)
+	c.backoff(errors.New("woops!"), now)
+	c.startedAt = now.Add(-time.Minute)
+	if d := c.backoff(errors.New("woops!"), now); d != time.Second {
+		t.Fatalf("got %v, want the backoff reset to 1s", d)
+	}
+}
+
+func TestRecordsEachRestartAttempt(t *testing.T) {
+	withoutJitter(t)
+	crashes := 0
+	logger, log := testLogger()
+	s := Start(Spec{Logger: logger, Intensity: &Intensity{MaxRestarts: 10}, Children: []ChildSpec{{
+		Name:    "fetcher",
+		Backoff: Backoff{Base: 5 * time.Millisecond, Max: 10 * time.Millisecond, Reset: time.Hour},
+		Start: func(ctx context.Context) error {
+			if crashes++; crashes <= 3 {
+				return fmt.Errorf("woops %d", crashes)
+			}
+			<-ctx.Done()
+			return nil
+		},
+	}}})
+	defer s.Shutdown()
+
+	var attempts []Attempt
+	eventually(t, func() bool {
+		attempts, _ = s.Restarts("fetcher")
+		return len(attempts) == 3
+	})
+
+	var got []string
+	for _, a := range attempts {
+		got = append(got, fmt.Sprint(a.Delay, " ", a.Reason))
+	}
+	if fmt.Sprint(got) != "[5ms woops 1 10ms woops 2 10ms woops 3]" {
+		t.Fatalf("got %v", got)
+	}
+	if !strings.Contains(log.String(), "Restarting fetcher in 10ms") {
+		t.Fatalf("got log %q", log.String())
+	}
+	if _, err := s.Restarts("nobody"); !errors.Is(err, ErrNoChild) {
+		t.Fatalf("got %v, want %v", err, ErrNoChild)
+	}
+}
@@ -0,0 +1,110 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by StartChild, TerminateChild and Children.
+var (
+	ErrNotSimple   = errors.New("supervisor: not a simple_one_for_one supervisor")
+	ErrMaxChildren = errors.New("supervisor: max children reached")
+	ErrNoChild     = errors.New("supervisor: no such child")
+	ErrNotRunning  = errors.New("supervisor: not running")
+)
+
+// Template describes the children of a SimpleOneForOne Supervisor. Each
+// child is named Name-N, for the Nth child started, and runs Start with the
+// args given to StartChild, on every restart.
+type Template struct {
+	Name     string
+	Start    func(ctx context.Context, args interface{}) error
+	Restart  Restart
+	Shutdown time.Duration
+	Backoff  Backoff
+}
+
+// StartChild starts a child from a SimpleOneForOne Supervisor's Template
+// with args, like supervisor:start_child/2, and returns its PID.
+func (s *Supervisor) StartChild(args interface{}) (PID, error) {
+	var pid PID
+	err := s.call(func(r *run) error {
+		if s.spec.Strategy != SimpleOneForOne {
+			return ErrNotSimple
+		}
+		if max := s.spec.MaxChildren; max > 0 && len(r.children) >= max {
+			return ErrMaxChildren
+		}
+
+		t := s.spec.Template
+		r.started++
+		c := &child{spec: ChildSpec{
+			Name:     fmt.Sprintf("%s-%d", t.Name, r.started),
+			Restart:  t.Restart,
+			Shutdown: t.Shutdown,
+			Backoff:  t.Backoff,
+			Start: func(ctx context.Context) error {
+				return t.Start(ctx, args)
+			},
+		}}
+		r.children = append(r.children, c)
+		r.start(c)
+		pid = c.pid
+		return nil
+	})
+	return pid, err
+}
+
+// TerminateChild stops the child named name, within its Shutdown, like
+// supervisor:terminate_child/2. A SimpleOneForOne Supervisor forgets the
+// child; any other keeps its spec, with the child stopped and not
+// restarted.
+func (s *Supervisor) TerminateChild(name string) error {
+	return s.call(func(r *run) error {
+		for _, c := range r.children {
+			if c.spec.Name == name {
+				c.waiting = false
+				r.terminate(c)
+				if s.spec.Strategy == SimpleOneForOne {
+					r.remove(c)
+				}
+				return nil
+			}
+		}
+		return ErrNoChild
+	})
+}
+
+// Children returns the PIDs of the Supervisor's children, in start order.
+// A child that has exited and is waiting on a restart keeps its last PID.
+func (s *Supervisor) Children() ([]PID, error) {
+	var pids []PID
+	err := s.call(func(r *run) error {
+		for _, c := range r.children {
+			pids = append(pids, c.pid)
+		}
+		return nil
+	})
+	return pids, err
+}
+
+// call runs f in the Supervisor's Run loop and returns its error, or
+// ErrNotRunning if Run isn't running.
+func (s *Supervisor) call(f func(r *run) error) error {
+	s.mu.Lock()
+	calls, stopped := s.calls, s.stopped
+	s.mu.Unlock()
+	if calls == nil {
+		return ErrNotRunning
+	}
+
+	errc := make(chan error, 1)
+	select {
+	case calls <- func(r *run) { errc <- f(r) }:
+		return <-errc
+	case <-stopped:
+		return ErrNotRunning
+	}
+}
@@ -0,0 +1,140 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// syncs is a Template worker that records the args of each start.
+type syncs struct {
+	mu   sync.Mutex
+	args []string
+}
+
+func (s *syncs) run(ctx context.Context, args interface{}) error {
+	s.mu.Lock()
+	s.args = append(s.args, args.(string))
+	s.mu.Unlock()
+
+	if args == "crash" && len(s.started()) < 3 {
+		return errors.New("woops!")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (s *syncs) started() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.args...)
+}
+
+func startSimple(t *testing.T, max int) (*Supervisor, *syncs) {
+	t.Helper()
+	w := &syncs{}
+	logger, _ := testLogger()
+	s := Start(Spec{
+		Strategy:    SimpleOneForOne,
+		Template:    Template{Name: "sync", Start: w.run},
+		MaxChildren: max,
+		Logger:      logger,
+	})
+	t.Cleanup(func() { s.Shutdown() })
+	return s, w
+}
+
+func TestStartsChildrenFromTheTemplate(t *testing.T) {
+	s, w := startSimple(t, 0)
+
+	for _, user := range []string{"ann", "bob"} {
+		if _, err := s.StartChild(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pids, err := s.Children()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pids) != 2 || pids[0].Name != "sync-1" || pids[1].Name != "sync-2" {
+		t.Fatalf("got %v, want sync-1 and sync-2", pids)
+	}
+	eventually(t, func() bool { return len(w.started()) == 2 })
+}
+
+func TestRestartsDynamicChildrenWithTheirArgs(t *testing.T) {
+	s, w := startSimple(t, 0)
+
+	first, _ := s.StartChild("crash")
+	eventually(t, func() bool { return len(w.started()) == 3 })
+
+	pids, _ := s.Children()
+	if len(pids) != 1 || pids[0].Name != first.Name || pids[0] == first {
+		t.Fatalf("got %v, want %s restarted", pids, first.Name)
+	}
+	if got := fmt.Sprint(w.started()); got != "[crash crash crash]" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestCapsTheChildCount(t *testing.T) {
+	s, _ := startSimple(t, 1)
+
+	s.StartChild("ann")
+	if _, err := s.StartChild("bob"); !errors.Is(err, ErrMaxChildren) {
+		t.Fatalf("got %v, want %v", err, ErrMaxChildren)
+	}
+}
+
+func TestTerminatesChildrenIndividually(t *testing.T) {
+	s, _ := startSimple(t, 1)
+
+	ann, _ := s.StartChild("ann")
+	if err := s.TerminateChild(ann.Name); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.TerminateChild(ann.Name); !errors.Is(err, ErrNoChild) {
+		t.Fatalf("got %v, want %v", err, ErrNoChild)
+	}
+	if pids, _ := s.Children(); len(pids) != 0 {
+		t.Fatalf("got %v, want no children", pids)
+	}
+	if _, err := s.StartChild("bob"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStaticChildrenKeepTheirSpecWhenTerminated(t *testing.T) {
+	logger, _ := testLogger()
+	w := &counter{}
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{Name: "fetcher", Start: w.run}}})
+	defer s.Shutdown()
+
+	if err := s.TerminateChild("fetcher"); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, _ := s.Info()
+	if len(infos) != 1 || infos[0].Name != "fetcher" || infos[0].State != Stopped || infos[0].LastExit != ExitShutdown {
+		t.Fatalf("got %+v, want fetcher kept and stopped", infos)
+	}
+	if w.count() != 1 {
+		t.Fatalf("got %d starts, want the child not restarted", w.count())
+	}
+}
+
+func TestStartChildNeedsASimpleOneForOneSupervisor(t *testing.T) {
+	logger, _ := testLogger()
+	s := Start(Spec{Logger: logger})
+	if _, err := s.StartChild("ann"); !errors.Is(err, ErrNotSimple) {
+		t.Fatalf("got %v, want %v", err, ErrNotSimple)
+	}
+
+	s.Shutdown()
+	if _, err := s.Children(); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("got %v, want %v", err, ErrNotRunning)
+	}
+}
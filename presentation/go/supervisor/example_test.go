@@ -0,0 +1,47 @@
+package supervisor_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/supervisor"
+)
+
+// Serve the tree where supctl tree looks for it by default.
+func ExampleSupervisor_Handler() {
+	fetcher := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+	s := supervisor.Start(supervisor.Spec{
+		Children: []supervisor.ChildSpec{{Name: "fetcher", Start: fetcher}},
+	})
+	defer s.Shutdown()
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/supervisor", s.Handler())
+	server := httptest.NewServer(mux) // or http.ListenAndServe("localhost:6060", mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/debug/supervisor")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var tree []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		log.Fatal(err)
+	}
+	for _, c := range tree {
+		fmt.Println(c.Name, c.State)
+	}
+	// Output: fetcher running
+}
@@ -0,0 +1,144 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// State is what a child is doing.
+type State string
+
+const (
+	// Running children are running.
+	Running State = "running"
+
+	// Restarting children have exited and are waiting out their Backoff.
+	Restarting State = "restarting"
+
+	// Stopped children have exited and won't be restarted.
+	Stopped State = "stopped"
+)
+
+// ChildInfo reports on a child, like a row of Erlang's observer. Children
+// holds the children of a child Supervisor.
+type ChildInfo struct {
+	Name     string
+	PID      PID
+	State    State
+	Restarts int
+	LastExit error
+	Uptime   time.Duration
+	Children []ChildInfo
+}
+
+// MarshalJSON encodes the PID's ID and the LastExit as strings and the
+// Uptime in seconds.
+func (c ChildInfo) MarshalJSON() ([]byte, error) {
+	var lastExit string
+	if c.LastExit != nil {
+		lastExit = c.LastExit.Error()
+	}
+	return json.Marshal(struct {
+		Name     string      `json:"name"`
+		PID      string      `json:"pid"`
+		State    State       `json:"state"`
+		Restarts int         `json:"restarts"`
+		LastExit string      `json:"last_exit,omitempty"`
+		Uptime   float64     `json:"uptime_seconds"`
+		Children []ChildInfo `json:"children,omitempty"`
+	}{c.Name, c.PID.ID.String(), c.State, c.Restarts, lastExit, c.Uptime.Seconds(), c.Children})
+}
+
+// Info reports on the Supervisor's children in start order, and on theirs
+// for child Supervisors.
+func (s *Supervisor) Info() ([]ChildInfo, error) {
+	var (
+		infos []ChildInfo
+		trees []*Supervisor
+	)
+	err := s.call(func(r *run) error {
+		now := time.Now()
+		for _, c := range r.children {
+			infos = append(infos, c.info(now))
+			trees = append(trees, c.spec.tree)
+		}
+		return nil
+	})
+
+	// Ask child Supervisors outside the Run loop, so a slow one doesn't
+	// hold up this one.
+	for i, tree := range trees {
+		if tree != nil && infos[i].State == Running {
+			infos[i].Children, _ = tree.Info()
+		}
+	}
+	return infos, err
+}
+
+func (c *child) info(now time.Time) ChildInfo {
+	info := ChildInfo{
+		Name:     c.spec.Name,
+		PID:      c.pid,
+		State:    Stopped,
+		Restarts: c.restarted,
+		LastExit: c.lastExit,
+	}
+	switch {
+	case c.running:
+		info.State = Running
+		info.Uptime = now.Sub(c.startedAt)
+	case c.waiting:
+		info.State = Restarting
+	}
+	return info
+}
+
+// WriteTree writes infos as an indented tree, one child per line.
+func WriteTree(w io.Writer, infos []ChildInfo) error {
+	return writeTree(w, infos, 0)
+}
+
+func writeTree(w io.Writer, infos []ChildInfo, depth int) error {
+	for _, c := range infos {
+		line := fmt.Sprintf("%s%s %s restarts=%d uptime=%v",
+			strings.Repeat("  ", depth), c.PID, c.State, c.Restarts, c.Uptime.Round(time.Second))
+		if c.LastExit != nil {
+			line += fmt.Sprintf(" last_exit=%q", c.LastExit.Error())
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if err := writeTree(w, c.Children, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the Supervisor's tree from Info as JSON, or as text from
+// WriteTree given ?format=text.
+func (s *Supervisor) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		infos, err := s.Info()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if req.URL.Query().Get("format") == "text" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			WriteTree(w, infos)
+			return
+		}
+		if infos == nil {
+			infos = []ChildInfo{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.Encode(infos)
+	})
+}
@@ -0,0 +1,94 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startObserved(t *testing.T) (*Supervisor, *crashable) {
+	t.Helper()
+	logger, _ := testLogger()
+	w := newCrashable()
+	workers := New(Spec{Logger: logger, Children: []ChildSpec{{Name: "fetcher", Start: w.run}}})
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{
+		{Name: "once", Restart: Transient, Start: func(ctx context.Context) error { return nil }},
+		workers.AsChild("workers"),
+	}})
+	t.Cleanup(func() { s.Shutdown() })
+	return s, w
+}
+
+func TestInfoReportsEachChild(t *testing.T) {
+	s, w := startObserved(t)
+	w.crash()
+
+	var infos []ChildInfo
+	eventually(t, func() bool {
+		infos, _ = s.Info()
+		return len(infos) == 2 && infos[0].State == Stopped &&
+			len(infos[1].Children) == 1 && infos[1].Children[0].Restarts == 1 &&
+			infos[1].Children[0].State == Running
+	})
+
+	once, fetcher := infos[0], infos[1].Children[0]
+	if once.LastExit != ExitNormal || once.Uptime != 0 {
+		t.Fatalf("got %+v, want a normal exit and no uptime", once)
+	}
+	if fetcher.LastExit == nil || fetcher.LastExit.Error() != "woops!" {
+		t.Fatalf("got %v, want woops!", fetcher.LastExit)
+	}
+	if infos[1].State != Running || infos[1].Uptime <= 0 {
+		t.Fatalf("got %+v, want workers running", infos[1])
+	}
+}
+
+func TestHandlerServesTheTree(t *testing.T) {
+	s, _ := startObserved(t)
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tree []struct {
+		Name     string
+		State    string
+		Children []struct{ Name string }
+	}
+	json.NewDecoder(resp.Body).Decode(&tree)
+	resp.Body.Close()
+	if len(tree) != 2 || tree[1].Name != "workers" || tree[1].Children[0].Name != "fetcher" {
+		t.Fatalf("got %+v", tree)
+	}
+
+	resp, err = server.Client().Get(server.URL + "?format=text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	lines := strings.Split(strings.TrimSpace(string(text)), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "workers<") || !strings.HasPrefix(lines[2], "  fetcher<") {
+		t.Fatalf("got\n%s", text)
+	}
+}
+
+func TestWriteTreeShowsTheLastExit(t *testing.T) {
+	var b strings.Builder
+	WriteTree(&b, []ChildInfo{{
+		PID:      PID{Name: "fetcher", ID: 7},
+		State:    Restarting,
+		Restarts: 2,
+		LastExit: ExitKilled,
+		Uptime:   1500 * time.Millisecond,
+	}})
+	if want := "fetcher<0.7.0> restarting restarts=2 uptime=2s last_exit=\"killed\"\n"; b.String() != want {
+		t.Fatalf("got %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,45 @@
+package supervisor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/process"
+)
+
+// Exit reasons for children that didn't fail. They are the process
+// package's reasons, so they compare equal to those.
+var (
+	// ExitNormal is the reason of a worker that returned nil.
+	ExitNormal = process.Normal
+
+	// ExitShutdown is the reason of a worker that returned nil after its
+	// supervisor stopped it.
+	ExitShutdown = process.Shutdown
+
+	// ExitKilled is the reason of a worker that didn't return within its
+	// shutdown timeout and was abandoned.
+	ExitKilled = process.Killed
+)
+
+// PanicError is the exit reason of a worker that panicked.
+type PanicError = process.PanicError
+
+// EscalationError is returned by a Supervisor that had to restart children
+// more often than its intensity allows. Child and Reason describe the exit
+// that tipped it over.
+type EscalationError struct {
+	Restarts int
+	Period   time.Duration
+	Child    PID
+	Reason   error
+}
+
+func (e *EscalationError) Error() string {
+	return fmt.Sprintf("supervisor: reached max restart intensity: %d restarts in %v: %s exited for reason %v",
+		e.Restarts, e.Period, e.Child, e.Reason)
+}
+
+func (e *EscalationError) Unwrap() error {
+	return e.Reason
+}
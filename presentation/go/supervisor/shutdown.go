@@ -0,0 +1,99 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Stop reports how a child stopped when its Supervisor shut down. A child
+// that outlived its grace period is TimedOut and has the Reason ExitKilled.
+// Children holds the report of a child Supervisor.
+type Stop struct {
+	Child    PID
+	Reason   error
+	Took     time.Duration
+	TimedOut bool
+	Children Report
+}
+
+// Report lists how a Supervisor's children stopped, in the order they were
+// stopped.
+type Report []Stop
+
+// Clean returns the children that stopped within their grace period.
+func (r Report) Clean() []Stop {
+	return r.filter(false)
+}
+
+// TimedOut returns the children that were abandoned.
+func (r Report) TimedOut() []Stop {
+	return r.filter(true)
+}
+
+func (r Report) filter(timedOut bool) []Stop {
+	var stops []Stop
+	for _, s := range r {
+		if s.TimedOut == timedOut {
+			stops = append(stops, s)
+		}
+	}
+	return stops
+}
+
+// String lists the children one per line, indenting those of child
+// Supervisors.
+func (r Report) String() string {
+	var b strings.Builder
+	r.write(&b, 0)
+	return b.String()
+}
+
+func (r Report) write(b *strings.Builder, depth int) {
+	for _, s := range r {
+		indent := strings.Repeat("  ", depth)
+		if s.TimedOut {
+			fmt.Fprintf(b, "%s%s timed out after %v\n", indent, s.Child, s.Took.Round(time.Millisecond))
+		} else {
+			fmt.Fprintf(b, "%s%s stopped cleanly in %v for reason %v\n", indent, s.Child, s.Took.Round(time.Millisecond), s.Reason)
+		}
+		s.Children.write(b, depth+1)
+	}
+}
+
+// Report returns how the Supervisor's children stopped the last time it
+// shut down or escalated, or nil if it hasn't.
+func (s *Supervisor) Report() Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.report
+}
+
+func (s *Supervisor) setReport(r Report) {
+	s.mu.Lock()
+	s.report = r
+	s.mu.Unlock()
+}
+
+// ShutdownOnSignal shuts down a Supervisor returned by Start when the
+// program receives one of sigs, SIGTERM or SIGINT if none are given. Wait
+// returns once the shutdown is done, and Report says how it went.
+func (s *Supervisor) ShutdownOnSignal(sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sigs...)
+	go func() {
+		defer signal.Stop(c)
+		select {
+		case sig := <-c:
+			s.spec.Logger.Printf("Shutting down on %v", sig)
+			s.cancel()
+		case <-s.done:
+		}
+	}()
+}
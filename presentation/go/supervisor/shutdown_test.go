@@ -0,0 +1,83 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReportsWhichChildrenStoppedCleanly(t *testing.T) {
+	logger, _ := testLogger()
+	release := make(chan struct{})
+	defer close(release)
+	drained := make(chan error, 1)
+
+	workers := New(Spec{Logger: logger, Children: []ChildSpec{{
+		Name: "draining",
+		Start: func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(5 * time.Millisecond)
+			drained <- context.Cause(ctx)
+			return nil
+		},
+	}}})
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{
+		workers.AsChild("workers"),
+		{
+			Name:     "stuck",
+			Shutdown: 10 * time.Millisecond,
+			Start: func(ctx context.Context) error {
+				<-release
+				return nil
+			},
+		},
+	}})
+
+	s.Shutdown()
+
+	if cause := <-drained; !errors.Is(cause, ExitShutdown) {
+		t.Fatalf("got cause %v, want %v", cause, ExitShutdown)
+	}
+	report := s.Report()
+	clean, timedOut := report.Clean(), report.TimedOut()
+	if len(timedOut) != 1 || timedOut[0].Child.Name != "stuck" || timedOut[0].Reason != ExitKilled {
+		t.Fatalf("got timed out %v", timedOut)
+	}
+	if len(clean) != 1 || clean[0].Child.Name != "workers" || clean[0].Reason != ExitShutdown {
+		t.Fatalf("got clean %v", clean)
+	}
+	if c := clean[0].Children; len(c) != 1 || c[0].Child.Name != "draining" || c[0].Took < 5*time.Millisecond {
+		t.Fatalf("got %v, want draining stopped after draining", c)
+	}
+
+	lines := strings.Split(report.String(), "\n")
+	if !strings.Contains(lines[0], "stuck<") || !strings.Contains(lines[0], "timed out after") ||
+		!strings.HasPrefix(lines[2], "  draining<") || !strings.Contains(lines[2], "stopped cleanly") {
+		t.Fatalf("got\n%s", report)
+	}
+}
+
+func TestShutsDownOnSignal(t *testing.T) {
+	logger, logs := testLogger()
+	w := &counter{}
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{Name: "worker", Start: w.run}}})
+	s.ShutdownOnSignal(syscall.SIGUSR1)
+
+	syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+
+	done := make(chan error)
+	go func() { done <- s.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		s.Shutdown()
+		t.Fatal("want the supervisor shut down")
+	}
+	if !strings.Contains(logs.String(), "Shutting down on user defined signal 1") || len(s.Report().Clean()) != 1 {
+		t.Fatalf("got\n%s\n%s", logs, s.Report())
+	}
+}
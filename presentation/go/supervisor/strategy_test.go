@@ -0,0 +1,159 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// crashable is a worker that runs until told to crash.
+type crashable struct {
+	counter
+	crashes chan struct{}
+}
+
+func newCrashable() *crashable {
+	return &crashable{crashes: make(chan struct{})}
+}
+
+func (c *crashable) run(ctx context.Context) error {
+	c.mu.Lock()
+	c.starts++
+	c.mu.Unlock()
+
+	select {
+	case <-c.crashes:
+		return errors.New("woops!")
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+func (c *crashable) crash() {
+	c.crashes <- struct{}{}
+}
+
+func startStrategy(t *testing.T, strategy Strategy, workers ...*crashable) *Supervisor {
+	logger, _ := testLogger()
+	spec := Spec{Strategy: strategy, Logger: logger}
+	for _, w := range workers {
+		spec.Children = append(spec.Children, ChildSpec{Name: "worker", Start: w.run})
+	}
+	s := Start(spec)
+	t.Cleanup(func() { s.Shutdown() })
+	for _, w := range workers {
+		eventually(t, func() bool { return w.count() == 1 })
+	}
+	return s
+}
+
+func TestOneForOneRestartsOnlyTheExitedChild(t *testing.T) {
+	a, b, c := newCrashable(), newCrashable(), newCrashable()
+	startStrategy(t, OneForOne, a, b, c)
+
+	b.crash()
+
+	eventually(t, func() bool { return b.count() == 2 })
+	if a.count() != 1 || c.count() != 1 {
+		t.Fatalf("got starts %d, %d; want 1, 1", a.count(), c.count())
+	}
+}
+
+func TestOneForAllRestartsEveryChild(t *testing.T) {
+	a, b, c := newCrashable(), newCrashable(), newCrashable()
+	startStrategy(t, OneForAll, a, b, c)
+
+	b.crash()
+
+	eventually(t, func() bool { return a.count() == 2 && b.count() == 2 && c.count() == 2 })
+}
+
+func TestRestForOneRestartsTheLaterChildren(t *testing.T) {
+	a, b, c := newCrashable(), newCrashable(), newCrashable()
+	startStrategy(t, RestForOne, a, b, c)
+
+	b.crash()
+
+	eventually(t, func() bool { return b.count() == 2 && c.count() == 2 })
+	if a.count() != 1 {
+		t.Fatalf("got %d starts of the earlier child, want 1", a.count())
+	}
+}
+
+func TestEscalatesPastTheRestartIntensity(t *testing.T) {
+	logger, _ := testLogger()
+	w := &counter{crash: 100}
+	s := Start(Spec{
+		Intensity: &Intensity{MaxRestarts: 2, Period: time.Minute},
+		Logger:    logger,
+		Children:  []ChildSpec{{Name: "worker", Start: w.run}},
+	})
+
+	var e *EscalationError
+	if err := s.Wait(); !errors.As(err, &e) || e.Restarts != 3 || e.Child.Name != "worker" {
+		t.Fatalf("got %v, want an EscalationError", err)
+	}
+	if w.count() != 3 {
+		t.Fatalf("got %d starts, want 3", w.count())
+	}
+}
+
+func TestZeroIntensityNeverRestarts(t *testing.T) {
+	logger, _ := testLogger()
+	w := &counter{crash: 100}
+	s := Start(Spec{
+		Intensity: &Intensity{MaxRestarts: 0},
+		Logger:    logger,
+		Children:  []ChildSpec{{Name: "worker", Start: w.run}},
+	})
+
+	var e *EscalationError
+	if err := s.Wait(); !errors.As(err, &e) || e.Restarts != 1 || e.Period != DefaultPeriod {
+		t.Fatalf("got %v, want an EscalationError on the first restart", err)
+	}
+	if w.count() != 1 {
+		t.Fatalf("got %d starts, want 1", w.count())
+	}
+}
+
+func TestRestartTypes(t *testing.T) {
+	tests := []struct {
+		restart Restart
+		fail    bool
+		starts  int
+	}{
+		{Permanent, false, 2},
+		{Transient, false, 1},
+		{Transient, true, 2},
+		{Temporary, true, 1},
+	}
+	for _, tt := range tests {
+		logger, _ := testLogger()
+		var starts int
+		exited := make(chan struct{}, 2)
+		s := Start(Spec{Logger: logger, Children: []ChildSpec{{
+			Name:    "worker",
+			Restart: tt.restart,
+			Start: func(ctx context.Context) error {
+				starts++
+				if starts > 1 {
+					<-ctx.Done()
+					return nil
+				}
+				defer func() { exited <- struct{}{} }()
+				if tt.fail {
+					return errors.New("woops!")
+				}
+				return nil
+			},
+		}}})
+		<-exited
+		time.Sleep(10 * time.Millisecond)
+		s.Shutdown()
+
+		if starts != tt.starts {
+			t.Errorf("restart %v, fail %v: got %d starts, want %d", tt.restart, tt.fail, starts, tt.starts)
+		}
+	}
+}
@@ -0,0 +1,499 @@
+// Package supervisor runs workers in goroutines and restarts them when they
+// exit, the way presentation/erlang/sup.erl does: workers express only
+// business logic and let it crash; the supervisor decides what happens next.
+package supervisor
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/process"
+)
+
+// Worker is the body of a supervised child. It should return once ctx is
+// done. Returning an error or panicking crashes the child. Each child runs
+// as a process; process.Self(ctx) returns it.
+type Worker func(ctx context.Context) error
+
+// Strategy says which children a Supervisor restarts when one exits.
+type Strategy int
+
+const (
+	// OneForOne restarts only the child that exited.
+	OneForOne Strategy = iota
+
+	// OneForAll stops the other children and restarts them all.
+	OneForAll
+
+	// RestForOne stops the children started after the one that exited and
+	// restarts it and them.
+	RestForOne
+
+	// SimpleOneForOne supervisors start with no children and start one
+	// from their Template per call to StartChild. Each is restarted on its
+	// own, as with OneForOne.
+	SimpleOneForOne
+)
+
+// Restart says when a child that exited is restarted.
+type Restart int
+
+const (
+	// Permanent children are always restarted.
+	Permanent Restart = iota
+
+	// Transient children are restarted only after an abnormal exit: one
+	// other than ExitNormal or ExitShutdown.
+	Transient
+
+	// Temporary children are never restarted.
+	Temporary
+)
+
+// Defaults used when a Spec or ChildSpec leaves a field unset.
+const (
+	DefaultMaxRestarts = 3
+	DefaultPeriod      = 5 * time.Second
+	DefaultShutdown    = 5 * time.Second
+)
+
+// Infinity, as a ChildSpec's Shutdown, waits for the child however long it
+// takes to stop.
+const Infinity time.Duration = -1
+
+// ChildSpec describes a supervised child.
+type ChildSpec struct {
+	Name    string
+	Start   Worker
+	Restart Restart
+
+	// Shutdown is the child's grace period: how long the supervisor waits
+	// for it to drain and return after cancelling its context, with cause
+	// ExitShutdown, before abandoning it with ExitKilled. It defaults to
+	// DefaultShutdown.
+	Shutdown time.Duration
+
+	// Backoff delays restarts of a child that keeps crashing.
+	Backoff Backoff
+
+	// Register, if set, is the name the child's process is registered
+	// under each time it starts, for process.Whereis, process.Ping and
+	// process.Alive.
+	Register string
+
+	// tree is set for a child that is itself a Supervisor.
+	tree *Supervisor
+}
+
+// Intensity bounds how often a Supervisor restarts its children, like the
+// intensity and period of an OTP supervisor. One that has to restart
+// children more than MaxRestarts times within Period stops them all and
+// exits with an *EscalationError, so a MaxRestarts of 0 escalates on the
+// first restart. Period defaults to DefaultPeriod.
+type Intensity struct {
+	MaxRestarts int
+	Period      time.Duration
+}
+
+// Spec describes a Supervisor.
+type Spec struct {
+	Strategy Strategy
+	Children []ChildSpec
+
+	// Intensity bounds how often the Supervisor restarts children. It
+	// defaults to DefaultMaxRestarts within DefaultPeriod.
+	Intensity *Intensity
+
+	// Logger receives a line for every child exit. It defaults to standard
+	// error.
+	Logger *log.Logger
+
+	// Template and MaxChildren configure a SimpleOneForOne Supervisor,
+	// which ignores Children. A MaxChildren of 0 means no cap.
+	Template    Template
+	MaxChildren int
+}
+
+// Supervisor starts its children and restarts each one that exits.
+type Supervisor struct {
+	spec      Spec
+	intensity Intensity
+
+	// Set while Run is running, for StartChild, TerminateChild and
+	// Children.
+	mu      sync.Mutex
+	calls   chan func(*run)
+	stopped chan struct{}
+
+	// report is how the children stopped, the last time Run returned.
+	report Report
+
+	// Set by Start.
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// New returns a Supervisor for spec. Call Run to start it.
+func New(spec Spec) *Supervisor {
+	if spec.Logger == nil {
+		spec.Logger = log.New(os.Stderr, "supervisor: ", log.LstdFlags)
+	}
+	intensity := Intensity{MaxRestarts: DefaultMaxRestarts}
+	if spec.Intensity != nil {
+		intensity = *spec.Intensity
+	}
+	if intensity.Period <= 0 {
+		intensity.Period = DefaultPeriod
+	}
+	return &Supervisor{spec: spec, intensity: intensity}
+}
+
+// Start runs a Supervisor for spec in a new goroutine, like sup:start/2,
+// and returns once its children are started.
+func Start(spec Spec) *Supervisor {
+	s := New(spec)
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		s.err = s.Run(context.WithValue(ctx, startedKey{}, started))
+		close(s.done)
+	}()
+	select {
+	case <-started:
+	case <-s.done:
+	}
+	return s
+}
+
+// Shutdown stops a Supervisor returned by Start, and its children, and
+// waits for them to exit.
+func (s *Supervisor) Shutdown() error {
+	s.cancel()
+	return s.Wait()
+}
+
+// Wait waits for a Supervisor returned by Start to exit and returns why.
+func (s *Supervisor) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// AsChild returns a ChildSpec that runs s under another Supervisor. The
+// parent waits for s to start all its children before starting its next
+// child, waits as long as s takes to shut down, and sees s exit with an
+// *EscalationError when s exceeds its restart intensity.
+func (s *Supervisor) AsChild(name string) ChildSpec {
+	return ChildSpec{Name: name, Start: s.Run, Shutdown: Infinity, tree: s}
+}
+
+// startedKey is the context key under which a parent passes the channel a
+// child Supervisor closes once its children are started.
+type startedKey struct{}
+
+// Run starts the children in order and supervises them until ctx is done,
+// then stops them in reverse order and returns nil. If the restart intensity
+// is exceeded, it stops the children and returns an *EscalationError.
+func (s *Supervisor) Run(ctx context.Context) error {
+	started, _ := ctx.Value(startedKey{}).(chan struct{})
+	ctx = context.WithValue(ctx, startedKey{}, nil)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	calls, stopped := make(chan func(*run)), make(chan struct{})
+	s.mu.Lock()
+	s.calls, s.stopped = calls, stopped
+	s.mu.Unlock()
+	defer close(stopped)
+
+	r := &run{
+		sup:     s,
+		ctx:     ctx,
+		exits:   make(chan exit),
+		delayed: make(chan delayed),
+	}
+	if s.spec.Strategy != SimpleOneForOne {
+		for _, spec := range s.spec.Children {
+			c := &child{spec: spec}
+			r.children = append(r.children, c)
+			r.start(c)
+		}
+	}
+	if started != nil {
+		close(started)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.setReport(r.terminateAll())
+			return nil
+		case f := <-calls:
+			f(r)
+		case d := <-r.delayed:
+			r.restartDelayed(d)
+		case e := <-r.exits:
+			if err := r.handle(e); err != nil {
+				s.setReport(r.terminateAll())
+				return err
+			}
+		}
+	}
+}
+
+// handle applies the restart type, intensity and strategy to an exit.
+func (r *run) handle(e exit) error {
+	c := e.child
+	if e.pid != c.pid || !c.running {
+		return nil
+	}
+	c.running = false
+	c.lastExit = e.reason
+	r.sup.spec.Logger.Printf("Process %s exited for reason %v", e.pid, e.reason)
+
+	if !c.restarts(e.reason) {
+		if c.spec.Restart == Temporary || r.sup.spec.Strategy == SimpleOneForOne {
+			r.remove(c)
+		}
+		return nil
+	}
+
+	now := time.Now()
+	r.restarts = append(r.restarts, now)
+	for len(r.restarts) > 0 && now.Sub(r.restarts[0]) > r.sup.intensity.Period {
+		r.restarts = r.restarts[1:]
+	}
+	if len(r.restarts) > r.sup.intensity.MaxRestarts {
+		return &EscalationError{
+			Restarts: len(r.restarts),
+			Period:   r.sup.intensity.Period,
+			Child:    e.pid,
+			Reason:   e.reason,
+		}
+	}
+
+	restart := func() {
+		switch r.sup.spec.Strategy {
+		case OneForAll:
+			r.restartFrom(0, c)
+		case RestForOne:
+			r.restartFrom(r.index(c), c)
+		default:
+			r.start(c)
+		}
+	}
+	d := c.backoff(e.reason, now)
+	if d <= 0 {
+		restart()
+		return nil
+	}
+	r.sup.spec.Logger.Printf("Restarting %s in %v", c.spec.Name, d)
+	c.waiting = true
+	r.after(d, c, restart)
+	return nil
+}
+
+// restartFrom stops the running children from index i on, in reverse
+// order, then starts failed and every stopped child that isn't Temporary.
+func (r *run) restartFrom(i int, failed *child) {
+	group := r.children[i:]
+	restart := map[*child]bool{failed: true}
+	for j := len(group) - 1; j >= 0; j-- {
+		c := group[j]
+		if !c.running {
+			continue
+		}
+		r.terminate(c)
+		if c.spec.Restart == Temporary {
+			r.remove(c)
+		} else {
+			restart[c] = true
+		}
+	}
+	for _, c := range r.children {
+		if restart[c] {
+			r.start(c)
+		}
+	}
+}
+
+func (r *run) index(c *child) int {
+	for i, other := range r.children {
+		if other == c {
+			return i
+		}
+	}
+	return len(r.children)
+}
+
+func (r *run) remove(c *child) {
+	i := r.index(c)
+	if i < len(r.children) {
+		r.children = append(r.children[:i], r.children[i+1:]...)
+	}
+}
+
+// run is the state of one call to Run.
+type run struct {
+	sup      *Supervisor
+	ctx      context.Context
+	children []*child
+	exits    chan exit
+	delayed  chan delayed
+
+	// restarts holds the times of recent restarts, for the intensity.
+	restarts []time.Time
+
+	// started counts the children started by StartChild, to name them.
+	started int
+}
+
+type child struct {
+	spec      ChildSpec
+	pid       PID
+	proc      *process.Process
+	running   bool
+	cancel    context.CancelCauseFunc
+	startedAt time.Time
+
+	// failures counts the restarts in a row, for the backoff, and
+	// attempts records them.
+	failures int
+	attempts []Attempt
+
+	// For Info.
+	restarted int
+	waiting   bool
+	lastExit  error
+}
+
+// restarts reports whether c's restart type calls for a restart after it
+// exited for reason.
+func (c *child) restarts(reason error) bool {
+	switch c.spec.Restart {
+	case Temporary:
+		return false
+	case Transient:
+		return reason != ExitNormal && reason != ExitShutdown
+	}
+	return true
+}
+
+type exit struct {
+	child  *child
+	pid    PID
+	reason error
+}
+
+// start runs c in a new process. If c is a Supervisor, start returns once
+// c has started its own children. c's context is cancelled only by
+// terminate, so children stop one at a time, in order.
+func (r *run) start(c *child) {
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(r.ctx))
+	if !c.startedAt.IsZero() {
+		c.restarted++
+	}
+	c.running = true
+	c.waiting = false
+	c.cancel = cancel
+	c.startedAt = time.Now()
+
+	var started chan struct{}
+	if c.spec.tree != nil {
+		started = make(chan struct{})
+		ctx = context.WithValue(ctx, startedKey{}, started)
+	}
+
+	p := process.Spawn(ctx, func(p *process.Process) error {
+		if c.spec.Register != "" {
+			if err := process.Register(c.spec.Register, p); err != nil {
+				return err
+			}
+		}
+		return c.spec.Start(p.Context())
+	})
+	c.proc = p
+	c.pid = PID{Name: c.spec.Name, ID: p.PID()}
+
+	pid := c.pid
+	go func() {
+		reason := p.Wait()
+		select {
+		case r.exits <- exit{child: c, pid: pid, reason: reason}:
+		case <-r.ctx.Done():
+		}
+	}()
+
+	if started != nil {
+		select {
+		case <-started:
+		case <-p.Done():
+		}
+	}
+}
+
+// terminateAll stops the children in reverse start order and reports how
+// they stopped.
+func (r *run) terminateAll() Report {
+	var report Report
+	for i := len(r.children) - 1; i >= 0; i-- {
+		if stop, ok := r.terminate(r.children[i]); ok {
+			report = append(report, stop)
+		}
+	}
+	return report
+}
+
+// terminate stops c, if it's running, and waits for it to exit for as long
+// as its Shutdown allows.
+func (r *run) terminate(c *child) (Stop, bool) {
+	if !c.running {
+		return Stop{}, false
+	}
+	c.running = false
+	c.cancel(ExitShutdown)
+	begin := time.Now()
+
+	d := c.spec.Shutdown
+	if d == 0 {
+		d = DefaultShutdown
+	}
+	var timeout <-chan time.Time
+	if d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	stop := Stop{Child: c.pid}
+	select {
+	case <-c.proc.Done():
+		c.lastExit = c.proc.Wait()
+	case <-timeout:
+		c.lastExit = ExitKilled
+		stop.TimedOut = true
+		process.Unregister(c.spec.Register, c.proc)
+		r.sup.spec.Logger.Printf("Process %s exited for reason %v", c.pid, ExitKilled)
+	}
+	stop.Reason = c.lastExit
+	stop.Took = time.Since(begin)
+	if c.spec.tree != nil && !stop.TimedOut {
+		stop.Children = c.spec.tree.Report()
+	}
+	return stop, true
+}
+
+// PID identifies one run of a child; a restarted child gets a new PID.
+type PID struct {
+	Name string
+	ID   process.PID
+}
+
+func (p PID) String() string {
+	return p.Name + p.ID.String()
+}
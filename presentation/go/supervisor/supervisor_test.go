@@ -0,0 +1,213 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwvoss/failure-patterns-essay/presentation/go/process"
+)
+
+// syncBuffer is a log destination safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func testLogger() (*log.Logger, *syncBuffer) {
+	b := &syncBuffer{}
+	return log.New(b, "", 0), b
+}
+
+// counter is a worker that counts its starts and crashes the first n.
+type counter struct {
+	mu     sync.Mutex
+	starts int
+	crash  int
+	panics bool
+}
+
+func (c *counter) run(ctx context.Context) error {
+	c.mu.Lock()
+	c.starts++
+	n := c.starts
+	c.mu.Unlock()
+
+	if n <= c.crash {
+		if c.panics {
+			panic("woops!")
+		}
+		return errors.New("woops!")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (c *counter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.starts
+}
+
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRestartsACrashedWorker(t *testing.T) {
+	logger, logs := testLogger()
+	w := &counter{crash: 2}
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{Name: "worker", Start: w.run}}})
+	defer s.Shutdown()
+
+	eventually(t, func() bool { return w.count() == 3 })
+	if got := strings.Count(logs.String(), "exited for reason woops!"); got != 2 {
+		t.Fatalf("got %d exit lines in\n%s", got, logs)
+	}
+}
+
+func TestRestartsAPanickedWorker(t *testing.T) {
+	logger, logs := testLogger()
+	w := &counter{crash: 1, panics: true}
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{Name: "worker", Start: w.run}}})
+	defer s.Shutdown()
+
+	eventually(t, func() bool { return w.count() == 2 })
+	if !strings.Contains(logs.String(), "exited for reason panic: woops!") {
+		t.Fatalf("got\n%s", logs)
+	}
+}
+
+func TestShutdownStopsTheChildren(t *testing.T) {
+	logger, _ := testLogger()
+	stopped := make(chan struct{})
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{Name: "worker", Start: func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	}}}})
+
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("want the child stopped")
+	}
+}
+
+func TestRegisteredChildrenKeepTheirNameAcrossRestarts(t *testing.T) {
+	logger, _ := testLogger()
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{
+		Name:     "fetcher",
+		Register: "rescuetime_fetcher",
+		Start: func(ctx context.Context) error {
+			self, _ := process.Self(ctx)
+			for {
+				msg, err := self.Receive(ctx)
+				if err != nil {
+					return nil
+				}
+				if msg == "crash" {
+					return errors.New("woops!")
+				}
+			}
+		},
+	}}})
+	defer s.Shutdown()
+
+	var first *process.Process
+	eventually(t, func() bool {
+		first, _ = process.Whereis("rescuetime_fetcher")
+		return first != nil
+	})
+	if err := process.Ping("rescuetime_fetcher", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	first.Send("crash")
+
+	eventually(t, func() bool {
+		p, _ := process.Whereis("rescuetime_fetcher")
+		return p != nil && p != first
+	})
+	if err := process.Ping("rescuetime_fetcher", time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlainWorkersAreAliveWithoutAnsweringPings(t *testing.T) {
+	logger, _ := testLogger()
+	w := &counter{}
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{Name: "plain", Register: "plain_worker", Start: w.run}}})
+	defer s.Shutdown()
+
+	eventually(t, func() bool {
+		_, ok := process.Whereis("plain_worker")
+		return ok
+	})
+	if err := process.Ping("plain_worker", 10*time.Millisecond); !errors.Is(err, process.ErrNoReply) {
+		t.Fatalf("got %v, want %v", err, process.ErrNoReply)
+	}
+	if !process.Alive("plain_worker") {
+		t.Fatal("want the worker reported alive")
+	}
+}
+
+func TestRestartedActorsStartWithAnEmptyMailbox(t *testing.T) {
+	handled := make(chan string)
+	a := process.NewActor(10, process.FailFast, func(ctx context.Context, inbox *process.Mailbox[string]) error {
+		for {
+			msg, err := inbox.Receive(ctx, process.Forever, nil)
+			if err != nil {
+				return nil
+			}
+			if msg == "crash" {
+				return errors.New("woops!")
+			}
+			handled <- msg
+		}
+	})
+	logger, _ := testLogger()
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{Name: "actor", Register: "actor", Start: a.Run}}})
+	defer s.Shutdown()
+
+	eventually(t, func() bool { return a.Send(context.Background(), "before") == nil })
+	a.Send(context.Background(), "crash")
+	a.Send(context.Background(), "lost")
+
+	if msg := <-handled; msg != "before" {
+		t.Fatalf("got %q, want before", msg)
+	}
+	eventually(t, func() bool { return a.Send(context.Background(), "after") == nil })
+	if msg := <-handled; msg != "after" {
+		t.Fatalf("got %q, want after; messages queued before the crash should be gone", msg)
+	}
+	if err := process.Ping("actor", time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
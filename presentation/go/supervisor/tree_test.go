@@ -0,0 +1,102 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// events records the order workers start and stop in.
+type events struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (e *events) add(s string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.log = append(e.log, s)
+}
+
+func (e *events) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.log, " ")
+}
+
+func (e *events) worker(name string) ChildSpec {
+	return ChildSpec{Name: name, Start: func(ctx context.Context) error {
+		e.add("start:" + name)
+		<-ctx.Done()
+		e.add("stop:" + name)
+		return nil
+	}}
+}
+
+func TestTreesStopInReverseOrder(t *testing.T) {
+	logger, _ := testLogger()
+	ev := &events{}
+	sub := New(Spec{Logger: logger, Children: []ChildSpec{ev.worker("b1"), ev.worker("b2")}})
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{
+		ev.worker("a"),
+		sub.AsChild("b"),
+		ev.worker("c"),
+	}})
+	eventually(t, func() bool { return strings.Count(ev.String(), "start:") == 4 })
+	s.Shutdown()
+
+	if got := ev.String(); !strings.HasSuffix(got, "stop:c stop:b2 stop:b1 stop:a") {
+		t.Fatalf("got %s, want stops in reverse order", got)
+	}
+}
+
+func TestChildSupervisorsEscalateToTheirParent(t *testing.T) {
+	logger, logs := testLogger()
+	w := &counter{crash: 100}
+	sub := New(Spec{
+		Intensity: &Intensity{MaxRestarts: 1, Period: time.Minute},
+		Logger:    logger,
+		Children:  []ChildSpec{{Name: "worker", Start: w.run}},
+	})
+	s := Start(Spec{
+		Intensity: &Intensity{MaxRestarts: 1, Period: time.Minute},
+		Logger:    logger,
+		Children:  []ChildSpec{sub.AsChild("sub")},
+	})
+
+	var e *EscalationError
+	err := s.Wait()
+	if !errors.As(err, &e) || e.Child.Name != "sub" {
+		t.Fatalf("got %v, want the parent to escalate", err)
+	}
+	var inner *EscalationError
+	if !errors.As(e.Reason, &inner) || inner.Child.Name != "worker" {
+		t.Fatalf("got %v, want the sub's escalation as the reason", e.Reason)
+	}
+	if w.count() != 4 {
+		t.Fatalf("got %d starts, want 4\n%s", w.count(), logs)
+	}
+}
+
+func TestAbandonsChildrenPastTheirShutdownTimeout(t *testing.T) {
+	logger, logs := testLogger()
+	release := make(chan struct{})
+	defer close(release)
+	s := Start(Spec{Logger: logger, Children: []ChildSpec{{
+		Name:     "stuck",
+		Shutdown: 10 * time.Millisecond,
+		Start: func(ctx context.Context) error {
+			<-release
+			return nil
+		},
+	}}})
+
+	s.Shutdown()
+
+	if !strings.Contains(logs.String(), "exited for reason killed") {
+		t.Fatalf("got\n%s", logs)
+	}
+}